@@ -0,0 +1,48 @@
+package logger
+
+import "strings"
+
+// StripANSI removes terminal escape sequences from s: colors and other CSI
+// sequences such as "\033[1;31m", and OSC sequences such as hyperlinks.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\033' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '[':
+			// Parameters and intermediates up to a final byte in @-~.
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+		case ']':
+			// Terminated by BEL or ESC \.
+			i += 2
+			for i < len(s) && s[i] != '\a' && !(s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\') {
+				i++
+			}
+			if i < len(s) && s[i] == '\033' {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return b.String()
+}
+
+// plainFormatter renders the console layout without any styling.
+var plainFormatter = &formatter{theme: &Theme{}}
+
+// PlainString renders e the way the console output does, without colors or
+// other escape codes.
+func (e Entry) PlainString() string {
+	return plainFormatter.console(e)
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ObjectStore uploads objects to blob storage. Adapters for S3, GCS and
+// Azure Blob Storage live in github.com/pecet3/logger/archive/....
+type ObjectStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+}
+
+// Archiver uploads completed log files to an ObjectStore. Set it in
+// FileOptions to archive every file closed by a FileSink, or call Archive
+// directly.
+type Archiver struct {
+	Store ObjectStore
+	// Prefix is prepended to the object keys, e.g. "logs/api/".
+	Prefix string
+	// Root is stripped from file paths to build keys; relative paths are
+	// used as they are when empty.
+	Root string
+	// Concurrency limits the number of uploads in flight, 4 by default.
+	Concurrency int
+	// DeleteAfterUpload removes local files once they are uploaded.
+	DeleteAfterUpload bool
+	// Logger, when set, receives an entry for every upload.
+	Logger *Logger
+	// WORM, when set, keeps DeleteAfterUpload from removing files within
+	// its retention period.
+	WORM *WORM
+
+	once sync.Once
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+type archiveEvent struct {
+	path, key string
+}
+
+func (ev archiveEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("path", ev.path)
+	enc.AddString("key", ev.key)
+}
+
+// Archive queues the upload of the file at path and returns immediately.
+func (a *Archiver) Archive(path string) {
+	a.once.Do(func() {
+		n := a.Concurrency
+		if n <= 0 {
+			n = 4
+		}
+		a.sem = make(chan struct{}, n)
+	})
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.sem <- struct{}{}
+		defer func() { <-a.sem }()
+		if err := a.upload(path); err != nil {
+			a.mu.Lock()
+			a.errs = append(a.errs, err)
+			a.mu.Unlock()
+			if a.Logger != nil {
+				a.Logger.Warn("archiving log file err: ", err)
+			}
+		}
+	}()
+}
+
+func (a *Archiver) key(file string) (string, error) {
+	rel := file
+	if a.Root != "" {
+		r, err := filepath.Rel(a.Root, file)
+		if err != nil {
+			return "", err
+		}
+		rel = r
+	}
+	rel = filepath.ToSlash(filepath.Clean(rel))
+	if strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside of %s", file, a.Root)
+	}
+	return path.Join(a.Prefix, strings.TrimLeft(rel, "/")), nil
+}
+
+func (a *Archiver) upload(file string) error {
+	key, err := a.key(file)
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	if err := a.Store.Put(context.Background(), key, f, info.Size()); err != nil {
+		return fmt.Errorf("archive: uploading %s: %w", file, err)
+	}
+	if a.Logger != nil {
+		a.Logger.Info("archived log file", archiveEvent{path: file, key: key})
+	}
+	if a.DeleteAfterUpload && a.WORM.allowDelete(file, "archived") {
+		f.Close()
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close waits for the queued uploads and returns their errors.
+func (a *Archiver) Close() error {
+	a.wg.Wait()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	err := errors.Join(a.errs...)
+	a.errs = nil
+	return err
+}
@@ -0,0 +1,31 @@
+// Package azblob uploads archived log files to Azure Blob Storage.
+package azblob
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+
+	"github.com/pecet3/logger"
+)
+
+// Store is a logger.ObjectStore writing to Container.
+type Store struct {
+	Client    *azblob.Client
+	Container string
+	// AccessTier, e.g. blob.AccessTierCool, the account default when empty.
+	AccessTier blob.AccessTier
+}
+
+var _ logger.ObjectStore = (*Store)(nil)
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	var opts *azblob.UploadStreamOptions
+	if s.AccessTier != "" {
+		opts = &azblob.UploadStreamOptions{AccessTier: &s.AccessTier}
+	}
+	_, err := s.Client.UploadStream(ctx, s.Container, key, r, opts)
+	return err
+}
@@ -0,0 +1,31 @@
+// Package gcs uploads archived log files to Google Cloud Storage.
+package gcs
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/pecet3/logger"
+)
+
+// Store is a logger.ObjectStore writing to Bucket.
+type Store struct {
+	Client *storage.Client
+	Bucket string
+	// StorageClass, e.g. "NEARLINE" or "ARCHIVE", the bucket default when empty.
+	StorageClass string
+}
+
+var _ logger.ObjectStore = (*Store)(nil)
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	w := s.Client.Bucket(s.Bucket).Object(key).NewWriter(ctx)
+	w.StorageClass = s.StorageClass
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,42 @@
+// Package s3 uploads archived log files to Amazon S3 or a compatible store.
+package s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/pecet3/logger"
+)
+
+// PutObjectAPI is the part of *s3.Client used by Store.
+type PutObjectAPI interface {
+	PutObject(ctx context.Context, in *s3.PutObjectInput, opts ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// Store is a logger.ObjectStore writing to Bucket.
+type Store struct {
+	Client PutObjectAPI
+	Bucket string
+	// StorageClass, e.g. types.StorageClassGlacierIr, the bucket default when empty.
+	StorageClass string
+}
+
+var _ logger.ObjectStore = (*Store)(nil)
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	in := &s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+	}
+	if s.StorageClass != "" {
+		in.StorageClass = types.StorageClass(s.StorageClass)
+	}
+	_, err := s.Client.PutObject(ctx, in)
+	return err
+}
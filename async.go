@@ -0,0 +1,264 @@
+package logger
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultAsyncBuffer is the queue size used when Async.BufferSize is not
+// set.
+const defaultAsyncBuffer = 1024
+
+// Async moves writing entries off the calling goroutine. Entries are
+// filtered, sampled and their caller captured where they are logged, then
+// queued for a background goroutine that writes them to the outputs and
+// sinks, batching the writes to sinks such as FileSink. Call Sync or Close
+// before exiting so queued entries are not lost; Fatal and Panic do it
+// themselves. Hooks and sinks run on the background goroutine: when they
+// call Sync, it writes the entries handled so far without waiting.
+type Async struct {
+	// BufferSize is the number of entries the queue holds, 1024 by default.
+	BufferSize int
+	// DropWhenFull drops entries when the queue is full instead of waiting
+	// for room. Dropped counts them.
+	DropWhenFull bool
+}
+
+// batchSink is implemented by sinks that write several entries at once
+// more cheaply than one by one.
+type batchSink interface {
+	WriteEntries(entries []Entry) error
+}
+
+type asyncWriter struct {
+	opts    Async
+	queue   chan asyncItem
+	stopped chan struct{}
+	dropped atomic.Int64
+	busy    atomic.Bool // set while the writer handles entries
+	batch   sinkBatch
+
+	mu     sync.RWMutex // held for reading while queueing, for writing to close
+	closed bool
+}
+
+// asyncItem is an entry logged by l, or a sync marker when done is set.
+type asyncItem struct {
+	l    *Logger
+	e    Entry
+	done chan struct{}
+}
+
+func newAsyncWriter(opts Async) *asyncWriter {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultAsyncBuffer
+	}
+	a := &asyncWriter{
+		opts:    opts,
+		queue:   make(chan asyncItem, opts.BufferSize),
+		stopped: make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// enqueue queues e, reporting false when the writer is closed and the
+// caller has to write it itself.
+func (a *asyncWriter) enqueue(l *Logger, e Entry) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return false
+	}
+	if !a.opts.DropWhenFull {
+		select {
+		case a.queue <- asyncItem{l: l, e: e}:
+			return true
+		default:
+		}
+		if a.onWriter() {
+			// The writer cannot wait for itself to make room.
+			return false
+		}
+		a.queue <- asyncItem{l: l, e: e}
+		return true
+	}
+	select {
+	case a.queue <- asyncItem{l: l, e: e}:
+	default:
+		a.dropped.Add(1)
+	}
+	return true
+}
+
+// sync waits until the entries queued so far are written. On the writer
+// goroutine, it writes the entries handled so far instead.
+func (a *asyncWriter) sync() {
+	if a == nil {
+		return
+	}
+	if a.onWriter() {
+		a.batch.write()
+		return
+	}
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	done := make(chan struct{})
+	a.queue <- asyncItem{done: done}
+	a.mu.RUnlock()
+	<-done
+}
+
+// close writes the queued entries and stops the writer.
+func (a *asyncWriter) close() {
+	if a == nil {
+		return
+	}
+	if a.onWriter() {
+		// Loggers queueing entries may wait for the writer to make room,
+		// so it cannot wait for them.
+		go a.close()
+		return
+	}
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+	<-a.stopped
+}
+
+// onWriter reports whether it is called from the writer goroutine, by a
+// hook or a sink. The writer marks when it handles entries, and only calls
+// made meanwhile look for it in their stack. The writer of another logger
+// is taken for this one, so the batch is locked.
+func (a *asyncWriter) onWriter() bool {
+	return a.busy.Load() && onWriterStack()
+}
+
+// writerEntry is the entry PC of run, the bottom frame of writer goroutines.
+var writerEntry atomic.Uintptr
+
+func onWriterStack() bool {
+	entry := writerEntry.Load()
+	pcs := make([]uintptr, 32)
+	for skip := 2; ; skip += len(pcs) {
+		n := runtime.Callers(skip, pcs)
+		frames := runtime.CallersFrames(pcs[:n])
+		for {
+			f, more := frames.Next()
+			if f.Entry == entry {
+				return true
+			}
+			if !more {
+				break
+			}
+		}
+		if n < len(pcs) {
+			return false
+		}
+	}
+}
+
+func (a *asyncWriter) run() {
+	defer close(a.stopped)
+	if pc, _, _, ok := runtime.Caller(0); ok {
+		writerEntry.CompareAndSwap(0, runtime.FuncForPC(pc).Entry())
+	}
+	b := &a.batch
+	for it := range a.queue {
+		a.busy.Store(true)
+		a.handle(it, b)
+		// Take whatever else is queued before writing the batches.
+	drain:
+		for n := 1; n < cap(a.queue); n++ {
+			select {
+			case next, ok := <-a.queue:
+				if !ok {
+					break drain
+				}
+				a.handle(next, b)
+			default:
+				break drain
+			}
+		}
+		b.write()
+		a.busy.Store(false)
+	}
+}
+
+func (a *asyncWriter) handle(it asyncItem, b *sinkBatch) {
+	if it.done != nil {
+		b.write()
+		close(it.done)
+		return
+	}
+	it.l.emit(it.e, func(sinks []Sink, e Entry) {
+		for _, s := range sinks {
+			if bs, ok := s.(batchSink); ok && reflect.TypeOf(s).Comparable() {
+				b.add(it.l, bs, e)
+				continue
+			}
+			it.l.writeSinks([]Sink{s}, e)
+		}
+	})
+}
+
+// sinkBatch collects the entries of batch sinks, in order per sink.
+type sinkBatch struct {
+	mu      sync.Mutex
+	sinks   []batchSink
+	loggers []*Logger
+	entries [][]Entry
+}
+
+func (b *sinkBatch) add(l *Logger, s batchSink, e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, bs := range b.sinks {
+		if bs == s {
+			b.entries[i] = append(b.entries[i], e)
+			return
+		}
+	}
+	b.sinks = append(b.sinks, s)
+	b.loggers = append(b.loggers, l)
+	b.entries = append(b.entries, []Entry{e})
+}
+
+// write writes the collected entries. The batch is taken first, as sinks
+// calling Sync write it again.
+func (b *sinkBatch) write() {
+	b.mu.Lock()
+	sinks, loggers, entries := b.sinks, b.loggers, b.entries
+	b.sinks, b.loggers, b.entries = nil, nil, nil
+	b.mu.Unlock()
+	for i, s := range sinks {
+		if err := s.WriteEntries(entries[i]); err != nil && loggers[i].c.IsDebugMode {
+			debug("writing entries err: ", err)
+		}
+	}
+}
+
+// Sync waits until the entries queued in Async mode are written and flushes
+// the sinks.
+func (l *Logger) Sync() error {
+	return l.flushSinks()
+}
+
+// Dropped returns the number of entries dropped because the Async queue was
+// full.
+func (l *Logger) Dropped() int64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.dropped.Load()
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Actor, Action and Target are the mandatory parts of an audit entry. They
+// are distinct types so the arguments of AuditLogger.Log cannot be swapped
+// by accident when passing typed values.
+type (
+	Actor  string
+	Action string
+	Target string
+)
+
+type AuditConfig struct {
+	// Name is attached to every entry, "audit" by default.
+	Name string
+	// Sinks receive every entry, e.g. a FileSink. Sinks with a Flush method
+	// are flushed after each entry, so it is durable once Log returns.
+	Sinks []Sink
+}
+
+// AuditLogger records who did what to which resource. Unlike Logger it
+// has no level, filter or sampler that could silence it, writes only to
+// its sinks and reports their errors instead of dropping entries.
+type AuditLogger struct {
+	name  string
+	sinks []Sink
+	mu    sync.Mutex
+}
+
+func NewAudit(cfg *AuditConfig) (*AuditLogger, error) {
+	if len(cfg.Sinks) == 0 {
+		return nil, errors.New("audit logger needs at least one sink")
+	}
+	name := cfg.Name
+	if name == "" {
+		name = "audit"
+	}
+	return &AuditLogger{name: name, sinks: append([]Sink(nil), cfg.Sinks...)}, nil
+}
+
+// Log writes an audit entry with the actor, action and target fields,
+// followed by the given fields, to every sink. It returns the errors of
+// the sinks that did not store the entry.
+func (a *AuditLogger) Log(actor Actor, action Action, target Target, fields ...LogMarshaler) error {
+	f := []Field{
+		{Key: "actor", Value: string(actor)},
+		{Key: "action", Value: string(action)},
+		{Key: "target", Value: string(target)},
+	}
+	for _, m := range fields {
+		f = append(f, marshalFields(m)...)
+	}
+	e := makeEntry(LevelInfo, string(action), f, captureCaller(2))
+	e.name = a.name
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var errs []error
+	for i, s := range a.sinks {
+		err := s.WriteEntry(e)
+		if fl, ok := s.(interface{ Flush() error }); ok && err == nil {
+			err = fl.Flush()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("audit sink %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"strings"
+	"time"
+)
+
+var messageTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05.000000",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+}
+
+// backfillTime gives a time to entries decoded without one: the leading
+// timestamp of the message when fromMessage is set and one is found,
+// otherwise readTime.
+func backfillTime(e *Entry, readTime time.Time, fromMessage bool) {
+	if !e.time.IsZero() {
+		return
+	}
+	if fromMessage {
+		if t, rest, ok := parseMessageTime(e.message); ok {
+			e.time = t
+			e.message = rest
+			return
+		}
+	}
+	e.time = readTime
+}
+
+// parseMessageTime parses a timestamp made of the first one or two words of
+// msg, returning the rest of the message.
+func parseMessageTime(msg string) (time.Time, string, bool) {
+	words := strings.SplitN(msg, " ", 3)
+	for n := 1; n <= 2 && n <= len(words); n++ {
+		candidate := strings.Join(words[:n], " ")
+		for _, layout := range messageTimeLayouts {
+			t, err := time.ParseInLocation(layout, candidate, time.Local)
+			if err != nil {
+				continue
+			}
+			return t, strings.TrimSpace(strings.TrimPrefix(msg, candidate)), true
+		}
+	}
+	return time.Time{}, msg, false
+}
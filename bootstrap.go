@@ -0,0 +1,97 @@
+package logger
+
+import "sync"
+
+// bootstrapLimit is the number of entries a Bootstrap holds; later ones are
+// counted and dropped.
+const bootstrapLimit = 1000
+
+// Bootstrap collects the entries logged during startup, before the
+// configuration of the real logger is known, and replays them through it
+// once Promote is called. Afterwards it forwards every entry to that logger.
+type Bootstrap struct {
+	mu      sync.Mutex
+	entries []Entry
+	dropped int
+	// promoted is set by Promote, and l once the entries are replayed.
+	promoted bool
+	l        *Logger
+}
+
+var _ Interface = (*Bootstrap)(nil)
+
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{}
+}
+
+func (b *Bootstrap) add(e Entry) {
+	b.mu.Lock()
+	l := b.l
+	if l == nil {
+		if len(b.entries) < bootstrapLimit {
+			b.entries = append(b.entries, e)
+		} else {
+			b.dropped++
+		}
+	}
+	b.mu.Unlock()
+	// Logged without b.mu, as hooks and sinks may log through b.
+	if l != nil && l.enabled(e.level) {
+		l.log(e)
+	}
+}
+
+// Promote replays the collected entries through l, with their original time
+// and caller and subject to the level of l, and makes b forward to l from
+// then on. Only the first call has an effect.
+func (b *Bootstrap) Promote(l *Logger) {
+	b.mu.Lock()
+	if b.promoted {
+		b.mu.Unlock()
+		return
+	}
+	b.promoted = true
+	// Entries logged through b during the replay are collected and
+	// replayed after the ones before them.
+	for len(b.entries) > 0 {
+		entries := b.entries
+		b.entries = nil
+		b.mu.Unlock()
+		for _, e := range entries {
+			if l.enabled(e.level) {
+				l.log(e)
+			}
+		}
+		b.mu.Lock()
+	}
+	b.l = l
+	dropped := b.dropped
+	b.mu.Unlock()
+	if dropped > 0 {
+		l.Warn("bootstrap buffer full", bootstrapEvent{dropped: dropped})
+	}
+}
+
+type bootstrapEvent struct {
+	dropped int
+}
+
+func (ev bootstrapEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddInt("dropped", int64(ev.dropped))
+}
+
+func (b *Bootstrap) Debug(args ...interface{}) {
+	b.add(newEntry(LevelDebug, args, captureCaller(2)))
+}
+
+func (b *Bootstrap) Info(args ...interface{}) {
+	b.add(newEntry(LevelInfo, args, nil))
+}
+
+func (b *Bootstrap) Warn(args ...interface{}) {
+	b.add(newEntry(LevelWarn, args, nil))
+}
+
+func (b *Bootstrap) Error(args ...interface{}) {
+	b.add(newEntry(LevelError, args, captureCaller(2)))
+}
@@ -0,0 +1,94 @@
+package logger
+
+import "sync"
+
+// Buffer holds entries in memory until Commit logs them or Discard drops
+// them, for operations whose logs should only appear if they went through
+// (or only if they failed). Entries keep the time and caller of the call
+// that produced them.
+type Buffer struct {
+	l *Logger
+
+	mu      sync.Mutex
+	entries []Entry
+	done    bool
+	kept    bool
+}
+
+var _ Interface = (*Buffer)(nil)
+
+func (l *Logger) Buffer() *Buffer {
+	return &Buffer{l: l}
+}
+
+func (b *Buffer) add(e Entry) {
+	b.mu.Lock()
+	if !b.done {
+		b.entries = append(b.entries, e)
+		b.mu.Unlock()
+		return
+	}
+	kept := b.kept
+	b.mu.Unlock()
+	if kept {
+		b.l.log(e)
+	}
+}
+
+// Commit logs the buffered entries in order. Entries added afterwards are
+// logged right away.
+func (b *Buffer) Commit() {
+	b.finish(true)
+}
+
+// Discard drops the buffered entries and every entry added afterwards.
+func (b *Buffer) Discard() {
+	b.finish(false)
+}
+
+func (b *Buffer) finish(keep bool) {
+	b.mu.Lock()
+	if b.done {
+		b.mu.Unlock()
+		return
+	}
+	entries := b.entries
+	b.entries = nil
+	b.done = true
+	b.kept = keep
+	b.mu.Unlock()
+	if !keep {
+		return
+	}
+	for _, e := range entries {
+		b.l.log(e)
+	}
+}
+
+func (b *Buffer) Debug(args ...interface{}) {
+	if !b.l.enabled(LevelDebug) {
+		return
+	}
+	b.add(newEntry(LevelDebug, args, b.l.caller(LevelDebug)))
+}
+
+func (b *Buffer) Info(args ...interface{}) {
+	if !b.l.enabled(LevelInfo) {
+		return
+	}
+	b.add(newEntry(LevelInfo, args, nil))
+}
+
+func (b *Buffer) Warn(args ...interface{}) {
+	if !b.l.enabled(LevelWarn) {
+		return
+	}
+	b.add(newEntry(LevelWarn, args, nil))
+}
+
+func (b *Buffer) Error(args ...interface{}) {
+	if !b.l.enabled(LevelError) {
+		return
+	}
+	b.add(newEntry(LevelError, args, b.l.caller(LevelError)))
+}
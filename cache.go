@@ -1,18 +1,111 @@
 package logger
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
 
-func (l *Logger) addCache(t time.Time, content string) {
-	l.cMu.Lock()
-	defer l.cMu.Unlock()
+// defaultCacheSize is the number of entries cached when Config.CacheSize is
+// not set.
+const defaultCacheSize = 1000
 
-	l.cache[t] = content
+// entryCache keeps the most recent entries, oldest first, for Entries, Flush
+// and the reports of the senders. It is shared by the loggers derived from
+// a logger.
+type entryCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries []Entry
 }
 
-func (l *Logger) cleanCache() {
-	l.cMu.Lock()
-	defer l.cMu.Unlock()
-	for key, _ := range l.cache {
-		delete(l.cache, key)
+func newEntryCache(size int, ttl time.Duration) *entryCache {
+	if size <= 0 {
+		size = defaultCacheSize
 	}
+	return &entryCache{size: size, ttl: ttl}
+}
+
+func (c *entryCache) add(e Entry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	if len(c.entries) >= c.size {
+		c.entries = c.entries[len(c.entries)-c.size+1:]
+	}
+	c.entries = append(c.entries, e)
+}
+
+// expire drops the entries older than the TTL.
+func (c *entryCache) expire(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	i := 0
+	for i < len(c.entries) && now.Sub(c.entries[i].time) > c.ttl {
+		i++
+	}
+	c.entries = c.entries[i:]
+}
+
+func (c *entryCache) get() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	return append([]Entry(nil), c.entries...)
+}
+
+func (c *entryCache) take() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	entries := c.entries
+	c.entries = nil
+	return entries
+}
+
+func (c *entryCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// Entries returns a copy of the cached entries, oldest first: the last
+// Config.CacheSize ones of every level, without those older than
+// Config.CacheTTL.
+func (l *Logger) Entries() []Entry {
+	if l.cache == nil {
+		return nil
+	}
+	return l.cache.get()
+}
+
+// Flush writes the cached entries to w, as plain text lines or as JSON with
+// FormatJSON, and empties the cache.
+func (l *Logger) Flush(w io.Writer) error {
+	if l.cache == nil {
+		return nil
+	}
+	for _, e := range l.cache.take() {
+		var line string
+		if l.c.Format == FormatJSON {
+			b, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("encoding entry: %w", err)
+			}
+			line = string(b)
+		} else {
+			line = l.f.plain(e)
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
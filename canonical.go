@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Canonical accumulates fields over the lifetime of an operation (typically
+// a request) and logs them as a single summary entry when Emit is called.
+type Canonical struct {
+	l       *Logger
+	message string
+	start   time.Time
+
+	mu      sync.Mutex
+	level   Level
+	fields  []Field
+	emitted bool
+}
+
+func (l *Logger) Canonical(message string) *Canonical {
+	return &Canonical{
+		l:       l,
+		message: message,
+		start:   time.Now(),
+		level:   LevelInfo,
+	}
+}
+
+// Set adds a field to the summary, replacing an earlier value of the key.
+func (c *Canonical) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.fields {
+		if c.fields[i].Key == key {
+			c.fields[i].Value = value
+			return
+		}
+	}
+	c.fields = append(c.fields, Field{Key: key, Value: value})
+}
+
+func (c *Canonical) SetFields(m LogMarshaler) {
+	if c == nil {
+		return
+	}
+	for _, f := range marshalFields(m) {
+		c.Set(f.Key, f.Value)
+	}
+}
+
+// Raise makes the summary be logged at least at lv, e.g. LevelError once
+// the operation failed.
+func (c *Canonical) Raise(lv Level) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if lv > c.level {
+		c.level = lv
+	}
+}
+
+// Emit logs the summary with a duration field. Only the first call logs.
+func (c *Canonical) Emit() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if c.emitted {
+		c.mu.Unlock()
+		return
+	}
+	c.emitted = true
+	fields := append(append([]Field(nil), c.fields...), Field{Key: "duration", Value: time.Since(c.start)})
+	level := c.level
+	c.mu.Unlock()
+
+	if !c.l.enabled(level) {
+		return
+	}
+	e := newEntry(level, []interface{}{c.message}, nil)
+	e.fields = append(e.fields, fields...)
+	c.l.log(e)
+}
+
+type canonicalKey struct{}
+
+func WithCanonical(ctx context.Context, c *Canonical) context.Context {
+	return context.WithValue(ctx, canonicalKey{}, c)
+}
+
+// CanonicalFrom returns the summary stored in ctx by WithCanonical, or nil.
+// Methods of a nil *Canonical do nothing, so callers need not check.
+func CanonicalFrom(ctx context.Context) *Canonical {
+	c, _ := ctx.Value(canonicalKey{}).(*Canonical)
+	return c
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+type CaptureOptions struct {
+	// Stdout and Stderr choose the streams to capture.
+	Stdout, Stderr bool
+	// StdoutLevel and StderrLevel are the levels of the captured lines,
+	// Info and Warn by default.
+	StdoutLevel, StderrLevel Level
+}
+
+// CaptureStdio redirects the process stdout and stderr, at the file
+// descriptor level, into a pipe and logs every line written to them as an
+// entry tagged "stdout" or "stderr", so the prints of dependencies and C
+// libraries end up in the structured stream. Outputs of l writing to
+// os.Stdout or os.Stderr keep going to the original streams. The returned
+// function restores the streams after logging the remaining lines.
+func (l *Logger) CaptureStdio(opts CaptureOptions) (stop func() error, err error) {
+	if opts.StdoutLevel == LevelDebug {
+		opts.StdoutLevel = LevelInfo
+	}
+	if opts.StderrLevel == LevelDebug {
+		opts.StderrLevel = LevelWarn
+	}
+	var stops []func() error
+	stopAll := func() error {
+		var errs []error
+		for i := len(stops) - 1; i >= 0; i-- {
+			errs = append(errs, stops[i]())
+		}
+		return errors.Join(errs...)
+	}
+	for _, s := range []struct {
+		on     bool
+		f      *os.File
+		origin string
+		level  Level
+	}{
+		{opts.Stdout, os.Stdout, "stdout", opts.StdoutLevel},
+		{opts.Stderr, os.Stderr, "stderr", opts.StderrLevel},
+	} {
+		if !s.on {
+			continue
+		}
+		stop, err := l.capture(s.f, s.origin, s.level)
+		if err != nil {
+			stopAll()
+			return nil, err
+		}
+		stops = append(stops, stop)
+	}
+	return stopAll, nil
+}
+
+func (l *Logger) capture(f *os.File, origin string, level Level) (func() error, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	orig, restore, err := redirectFile(f, w)
+	w.Close()
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	l.o.swap(f, orig)
+
+	done := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(done)
+		readErr = l.Tag(origin).drainCaptured(r, origin, level)
+		r.Close()
+	}()
+	return func() error {
+		// Restoring the descriptor closes the last write end of the pipe,
+		// so the reader drains it and stops.
+		err := restore()
+		<-done
+		l.o.swap(orig, f)
+		return errors.Join(err, readErr, orig.Close())
+	}, nil
+}
+
+// captureChunk is the longest entry made of a captured line; longer lines
+// are split into several entries.
+const captureChunk = 64 << 10
+
+// drainCaptured logs the lines read from r until the write end is closed.
+// A failed read is reported and the rest of r discarded, so the process
+// writing to it never blocks or gets SIGPIPE.
+func (l *Logger) drainCaptured(r io.Reader, origin string, level Level) error {
+	br := bufio.NewReaderSize(r, captureChunk)
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == nil {
+			line = bytes.TrimSuffix(line[:len(line)-1], []byte("\r"))
+		}
+		if len(line) > 0 && l.enabled(level) {
+			l.log(makeEntry(level, string(line), nil, nil))
+		}
+		switch {
+		case err == nil, errors.Is(err, bufio.ErrBufferFull):
+		case errors.Is(err, io.EOF):
+			return nil
+		default:
+			err = fmt.Errorf("capture %s: %w", origin, err)
+			l.ErrorErr(err)
+			_, cerr := io.Copy(io.Discard, r)
+			return errors.Join(err, cerr)
+		}
+	}
+}
+
+// swap replaces the writers equal to from with to.
+func (o *outputs) swap(from, to io.Writer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.w == from {
+		o.w = to
+	}
+	if o.errW == from {
+		o.errW = to
+	}
+}
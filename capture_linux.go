@@ -0,0 +1,8 @@
+package logger
+
+import "syscall"
+
+// dup2 uses Dup3, as linux/arm64 has no dup2 system call.
+func dup2(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
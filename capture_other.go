@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package logger
+
+import (
+	"errors"
+	"os"
+)
+
+func redirectFile(f, w *os.File) (*os.File, func() error, error) {
+	return nil, nil, errors.New("capturing stdio is not supported on this platform")
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// redirectFile points the descriptor of f to w. It returns a file for the
+// original stream and a function pointing the descriptor back to it.
+func redirectFile(f, w *os.File) (*os.File, func() error, error) {
+	fd := int(f.Fd())
+	saved, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := dup2(int(w.Fd()), fd); err != nil {
+		syscall.Close(saved)
+		return nil, nil, err
+	}
+	orig := os.NewFile(uintptr(saved), f.Name())
+	return orig, func() error { return dup2(saved, fd) }, nil
+}
@@ -0,0 +1,63 @@
+// Package clilog wires github.com/pecet3/logger into urfave/cli apps.
+package clilog
+
+import (
+	"github.com/pecet3/logger"
+	"github.com/urfave/cli/v2"
+)
+
+const metadataKey = "logger"
+
+// Flags returns the logger flags (-v/-vv, --quiet, --log-format,
+// --log-file) to append to an app's Flags. Set App.UseShortOptionHandling
+// for -vv to count twice.
+func Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "verbose",
+			Aliases: []string{"v"},
+			Usage:   "verbose output, repeat for debug messages",
+			Count:   new(int),
+		},
+		&cli.BoolFlag{Name: "quiet", Aliases: []string{"q"}, Usage: "only show errors"},
+		&cli.StringFlag{Name: "log-format", Value: "console", Usage: "log output format: console or json"},
+		&cli.StringFlag{Name: "log-file", Usage: "append logs to the given file instead of stdout"},
+	}
+}
+
+// Before returns a cli.BeforeFunc that builds a logger from base and the
+// parsed flags, installs it with logger.SetDefault and stores it in the app
+// metadata for FromContext.
+func Before(base *logger.Config) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		f := &logger.Flags{
+			Verbosity: c.Count("verbose"),
+			Quiet:     c.Bool("quiet"),
+			Format:    c.String("log-format"),
+			File:      c.String("log-file"),
+		}
+		config := logger.Config{}
+		if base != nil {
+			config = *base
+		}
+		if err := f.Apply(&config); err != nil {
+			return err
+		}
+		l := logger.New(&config)
+		logger.SetDefault(l)
+		if c.App.Metadata == nil {
+			c.App.Metadata = make(map[string]interface{})
+		}
+		c.App.Metadata[metadataKey] = l
+		return nil
+	}
+}
+
+// FromContext returns the logger set up by Before, falling back to the
+// package default.
+func FromContext(c *cli.Context) *logger.Logger {
+	if l, ok := c.App.Metadata[metadataKey].(*logger.Logger); ok {
+		return l
+	}
+	return logger.Default()
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of the entry times, the system clock by default.
+type Clock interface {
+	Now() time.Time
+}
+
+// OffsetClock is a Clock that knows how far it was measured to be from
+// its reference, e.g. the offset to the NTP server or PTP grandmaster. Its
+// entries get the offset as a clock_offset field.
+type OffsetClock interface {
+	Clock
+	Offset() time.Duration
+}
+
+// ClockFunc adapts a function to Clock, e.g. one reading a PTP hardware
+// clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// clockHolder is a clock source and the latest entry time read from it,
+// in Unix nanoseconds, to notice it being stepped back.
+type clockHolder struct {
+	c    Clock
+	last atomic.Int64
+}
+
+var (
+	clock       atomic.Pointer[clockHolder]
+	systemClock clockHolder
+)
+
+// SetClock makes every logger take entry times from c, or from the system
+// clock again when c is nil. Audit logs can so use a disciplined clock
+// instead of a wall clock that may drift or be stepped.
+func SetClock(c Clock) {
+	if c == nil {
+		clock.Store(nil)
+		return
+	}
+	clock.Store(&clockHolder{c: c})
+}
+
+// entryTime returns the entry time, how far the clock was stepped back
+// before it and, for an OffsetClock, its offset field.
+func entryTime() (time.Time, time.Duration, *Field) {
+	h := clock.Load()
+	if h == nil {
+		h = &systemClock
+	}
+	t, skew := h.read()
+	if oc, ok := h.c.(OffsetClock); ok {
+		return t, skew, &Field{Key: "clock_offset", Value: oc.Offset()}
+	}
+	return t, skew, nil
+}
+
+func (h *clockHolder) now() time.Time {
+	if h.c == nil {
+		return time.Now()
+	}
+	return h.c.Now()
+}
+
+// read returns the time of a new entry and, when the clock went backwards
+// since the previous one, by how much. The latest time then restarts from
+// the stepped clock, so only the first entry after a step reports it.
+func (h *clockHolder) read() (time.Time, time.Duration) {
+	t := h.now()
+	reread := false
+	for {
+		last := h.last.Load()
+		wall := t.UnixNano()
+		if wall < last && !reread {
+			// Another entry may have read the clock after this one but
+			// stored its time first: only a second read behind is a step.
+			t, reread = h.now(), true
+			continue
+		}
+		if h.last.CompareAndSwap(last, wall) {
+			if wall < last {
+				return t, time.Duration(last - wall)
+			}
+			return t, 0
+		}
+	}
+}
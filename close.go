@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"strings"
+	"time"
+)
+
+// maxHeatColumns bounds the width of the heat-map; longer lifetimes merge
+// several minutes into one column.
+const maxHeatColumns = 120
+
+var (
+	heatShades      = []rune("·▁▂▃▄▅▆▇█")
+	heatShadesASCII = []rune(".:-=+*#%@")
+)
+
+// Close logs the heat-map summary when Config.HeatMap is set, stops the
+// Async writer after the queued entries and the Events summaries, and
+// flushes the sinks, returning the first error. The logger can still be used afterwards, writing
+// synchronously.
+func (l *Logger) Close() error {
+	if l.c.HeatMap && l.enabled(LevelInfo) {
+		l.log(makeEntry(LevelInfo, "log summary", marshalFields(l.heatMap(time.Now())), nil))
+	}
+	if l.c.Events != nil {
+		l.c.Events.Stop()
+	}
+	l.async.close()
+	return l.flushSinks()
+}
+
+func (l *Logger) heatMap(now time.Time) heatMapEvent {
+	lifetime := min(now.Sub(processStart), 24*time.Hour)
+	minutes := int(lifetime/time.Minute) + 1
+	per := (minutes + maxHeatColumns - 1) / maxHeatColumns
+	columns := (minutes + per - 1) / per
+	return heatMapEvent{
+		column:  time.Duration(per) * time.Minute,
+		buckets: l.vol.histogram(now, time.Duration(columns*per)*time.Minute, columns),
+		shades:  heatShadesFor(unicodeSupported()),
+	}
+}
+
+func heatShadesFor(unicode bool) []rune {
+	if unicode {
+		return heatShades
+	}
+	return heatShadesASCII
+}
+
+type heatMapEvent struct {
+	column  time.Duration
+	buckets []VolumeBucket
+	shades  []rune
+}
+
+func (ev heatMapEvent) MarshalLog(enc FieldEncoder) {
+	if len(ev.buckets) > 0 {
+		enc.AddTime("start", ev.buckets[0].Start)
+	}
+	enc.AddDuration("column", ev.column)
+	for lv := LevelDebug; lv <= LevelFatal; lv++ {
+		total, peak := 0, 0
+		for _, b := range ev.buckets {
+			total += b.Counts[lv]
+			peak = max(peak, b.Counts[lv])
+		}
+		if total == 0 {
+			continue
+		}
+		enc.AddObject(lv.String(), heatRow{lv: lv, total: total, peak: peak, ev: ev})
+	}
+}
+
+// heatRow is the line of a level: its total and one shade per column,
+// scaled to the busiest column.
+type heatRow struct {
+	lv          Level
+	total, peak int
+	ev          heatMapEvent
+}
+
+func (r heatRow) MarshalLog(enc FieldEncoder) {
+	enc.AddInt("total", int64(r.total))
+	var b strings.Builder
+	steps := len(r.ev.shades) - 1
+	for _, bucket := range r.ev.buckets {
+		n := bucket.Counts[r.lv]
+		i := 0
+		if n > 0 {
+			i = 1 + (n*steps-1)/r.peak
+			i = min(i, steps)
+		}
+		b.WriteRune(r.ev.shades[i])
+	}
+	enc.AddString("map", b.String())
+}
@@ -0,0 +1,40 @@
+// Package cobralog wires github.com/pecet3/logger into cobra commands.
+package cobralog
+
+import (
+	"github.com/pecet3/logger"
+	"github.com/spf13/cobra"
+)
+
+// Bind registers the logger flags (-v/-vv, --quiet, --log-format,
+// --log-file) as persistent flags of cmd. Before any command runs, a logger
+// is built from base and the parsed flags and installed with
+// logger.SetDefault.
+//
+// Cobra only runs the closest PersistentPreRunE; set
+// cobra.EnableTraverseRunHooks when subcommands define their own.
+func Bind(cmd *cobra.Command, base *logger.Config) *logger.Flags {
+	f := &logger.Flags{Format: "console"}
+	pf := cmd.PersistentFlags()
+	pf.CountVarP(&f.Verbosity, "verbose", "v", "verbose output, repeat for debug messages")
+	pf.BoolVarP(&f.Quiet, "quiet", "q", false, "only show errors")
+	pf.StringVar(&f.Format, "log-format", f.Format, "log output format: console or json")
+	pf.StringVar(&f.File, "log-file", "", "append logs to the given file instead of stdout")
+
+	prev := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		config := logger.Config{}
+		if base != nil {
+			config = *base
+		}
+		if err := f.Apply(&config); err != nil {
+			return err
+		}
+		logger.SetDefault(logger.New(&config))
+		if prev != nil {
+			return prev(c, args)
+		}
+		return nil
+	}
+	return f
+}
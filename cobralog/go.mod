@@ -0,0 +1,13 @@
+module github.com/pecet3/logger/cobralog
+
+go 1.23.4
+
+require (
+	github.com/pecet3/logger v0.1.0
+	github.com/spf13/cobra v1.10.2
+)
+
+require (
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/spf13/pflag v1.0.9 // indirect
+)
@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RGB returns a Style setting a 24-bit foreground color. On terminals with
+// fewer colors the closest one available is used.
+func RGB(r, g, b uint8) Style {
+	return Style(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// BgRGB is RGB for the background.
+func BgRGB(r, g, b uint8) Style {
+	return Style(fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b))
+}
+
+// Fg256 returns a Style setting a foreground color of the 256-color
+// palette.
+func Fg256(n uint8) Style {
+	return Style(fmt.Sprintf("\033[38;5;%dm", n))
+}
+
+// Bg256 is Fg256 for the background.
+func Bg256(n uint8) Style {
+	return Style(fmt.Sprintf("\033[48;5;%dm", n))
+}
+
+// ParseHex parses a "#rrggbb" or "#rgb" foreground color.
+func ParseHex(s string) (Style, error) {
+	r, g, b, err := parseHex(s)
+	if err != nil {
+		return "", err
+	}
+	return RGB(r, g, b), nil
+}
+
+// Hex is like ParseHex but panics on invalid colors, for themes defined in
+// package variables.
+func Hex(s string) Style {
+	st, err := ParseHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return st
+}
+
+// BgHex is Hex for the background.
+func BgHex(s string) Style {
+	r, g, b, err := parseHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return BgRGB(r, g, b)
+}
+
+func parseHex(s string) (r, g, b uint8, err error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, perr := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || perr != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hex color: %q", s)
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
+
+// ansi16 is the xterm palette of the 16 basic colors.
+var ansi16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// palette256 returns the color of a 256-color palette index.
+func palette256(n int) [3]uint8 {
+	switch {
+	case n < 16:
+		return ansi16[n]
+	case n < 232:
+		n -= 16
+		return [3]uint8{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	}
+	v := uint8(8 + (n-232)*10)
+	return [3]uint8{v, v, v}
+}
+
+func nearest(c [3]uint8, candidates func(i int) [3]uint8, n int) int {
+	best, bestDist := 0, -1
+	for i := 0; i < n; i++ {
+		p := candidates(i)
+		dist := 0
+		for j := range c {
+			d := int(c[j]) - int(p[j])
+			dist += d * d
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// Downsample converts the 24-bit and 256-color codes of s to the closest
+// ones the given depth supports. Other codes are kept.
+func (s Style) Downsample(depth ColorDepth) Style {
+	if depth == ColorTrueColor || depth == ColorNone || s == "" {
+		return s
+	}
+	var b strings.Builder
+	for _, seq := range strings.Split(string(s), "\033[") {
+		params, ok := strings.CutSuffix(seq, "m")
+		if !ok {
+			b.WriteString(seq)
+			continue
+		}
+		b.WriteString("\033[" + downsampleParams(strings.Split(params, ";"), depth) + "m")
+	}
+	return Style(b.String())
+}
+
+func downsampleParams(params []string, depth ColorDepth) string {
+	out := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		if (p != "38" && p != "48") || i+1 >= len(params) {
+			out = append(out, p)
+			continue
+		}
+		var c [3]uint8
+		var n int
+		switch {
+		case params[i+1] == "2" && i+4 < len(params):
+			for j := range c {
+				v, _ := strconv.Atoi(params[i+2+j])
+				c[j] = uint8(v)
+			}
+			n = -1
+			i += 4
+		case params[i+1] == "5" && i+2 < len(params):
+			n, _ = strconv.Atoi(params[i+2])
+			c = palette256(n)
+			i += 2
+		default:
+			out = append(out, p)
+			continue
+		}
+		if depth == Color256 {
+			if n < 0 {
+				// The first 16 colors vary with the terminal theme, only the
+				// cube and the grays are fixed.
+				n = 16 + nearest(c, func(i int) [3]uint8 { return palette256(16 + i) }, 240)
+			}
+			out = append(out, p, "5", strconv.Itoa(n))
+			continue
+		}
+		idx := nearest(c, func(i int) [3]uint8 { return ansi16[i] }, 16)
+		code := 30 + idx
+		if idx >= 8 {
+			code = 90 + idx - 8
+		}
+		if p == "48" {
+			code += 10
+		}
+		out = append(out, strconv.Itoa(code))
+	}
+	return strings.Join(out, ";")
+}
+
+// Downsample returns a copy of t whose colors fit the given depth.
+func (t *Theme) Downsample(depth ColorDepth) *Theme {
+	if depth == ColorTrueColor || depth == ColorNone {
+		return t
+	}
+	c := *t
+	for _, s := range []*Style{&c.Badge, &c.Date, &c.Time, &c.Uptime, &c.Name, &c.Caller, &c.Line, &c.Message, &c.Detail, &c.FieldKey, &c.Tag, &c.DumpType, &c.DumpString, &c.DumpLiteral} {
+		*s = s.Downsample(depth)
+	}
+	c.Levels = downsampleLevels(t.Levels, depth)
+	c.Highlights = downsampleLevels(t.Highlights, depth)
+	c.Markers = downsampleLevels(t.Markers, depth)
+	c.Names = downsampleNames(t.Names, depth)
+	c.Tags = downsampleNames(t.Tags, depth)
+	return &c
+}
+
+func downsampleLevels(m map[Level]Style, depth ColorDepth) map[Level]Style {
+	if m == nil {
+		return nil
+	}
+	out := make(map[Level]Style, len(m))
+	for k, s := range m {
+		out[k] = s.Downsample(depth)
+	}
+	return out
+}
+
+func downsampleNames(m map[string]Style, depth ColorDepth) map[string]Style {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]Style, len(m))
+	for k, s := range m {
+		out[k] = s.Downsample(depth)
+	}
+	return out
+}
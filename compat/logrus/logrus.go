@@ -0,0 +1,158 @@
+// Package logrus exposes the logrus API on top of a logger.Logger, so code
+// written for github.com/sirupsen/logrus can switch by changing its import:
+//
+//	import log "github.com/pecet3/logger/compat/logrus"
+//
+//	log.WithFields(log.Fields{"user": id}).Infof("logged in after %v", d)
+//
+// Only the logging methods are provided; hooks, formatters and outputs are
+// configured on the wrapped logger.
+package logrus
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pecet3/logger"
+)
+
+// Fields attaches key-value pairs to an entry.
+type Fields map[string]interface{}
+
+// Level mirrors the logrus levels, which count down from PanicLevel.
+type Level uint32
+
+const (
+	PanicLevel Level = iota
+	FatalLevel
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+// ErrorKey is the field key of WithError.
+var ErrorKey = "error"
+
+func (lv Level) level() logger.Level {
+	switch lv {
+	case PanicLevel:
+		return logger.LevelPanic
+	case FatalLevel:
+		return logger.LevelFatal
+	case ErrorLevel:
+		return logger.LevelError
+	case WarnLevel:
+		return logger.LevelWarn
+	case InfoLevel:
+		return logger.LevelInfo
+	}
+	return logger.LevelDebug
+}
+
+// Logger is a logrus logger writing through a logger.Logger.
+type Logger struct {
+	l *logger.Logger
+}
+
+// New returns a logger writing to stderr like logrus.New.
+func New() *Logger {
+	return Wrap(logger.New(&logger.Config{Output: os.Stderr}))
+}
+
+// Wrap returns a logrus logger writing through l.
+func Wrap(l *logger.Logger) *Logger {
+	return &Logger{l: l.CallerSkip(1)}
+}
+
+// Unwrap returns the logger written through.
+func (l *Logger) Unwrap() *logger.Logger {
+	return l.l.CallerSkip(-1)
+}
+
+func (l *Logger) SetLevel(lv Level) {
+	l.l.SetLevel(lv.level())
+}
+
+func (l *Logger) WithField(key string, value interface{}) *Entry {
+	return &Entry{l: l.l.WithFields(Fields{key: value})}
+}
+
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return &Entry{l: l.l.WithFields(fields)}
+}
+
+func (l *Logger) WithError(err error) *Entry {
+	return &Entry{l: l.l.WithFields(Fields{ErrorKey: err})}
+}
+
+func (l *Logger) Trace(args ...interface{})   { l.l.Debug(args...) }
+func (l *Logger) Debug(args ...interface{})   { l.l.Debug(args...) }
+func (l *Logger) Info(args ...interface{})    { l.l.Info(args...) }
+func (l *Logger) Print(args ...interface{})   { l.l.Info(args...) }
+func (l *Logger) Warn(args ...interface{})    { l.l.Warn(args...) }
+func (l *Logger) Warning(args ...interface{}) { l.l.Warn(args...) }
+func (l *Logger) Error(args ...interface{})   { l.l.Error(args...) }
+func (l *Logger) Fatal(args ...interface{})   { l.l.Fatal(args...) }
+func (l *Logger) Panic(args ...interface{})   { l.l.Panic(args...) }
+
+func (l *Logger) Tracef(format string, args ...interface{})   { l.l.Debugf(format, args...) }
+func (l *Logger) Debugf(format string, args ...interface{})   { l.l.Debugf(format, args...) }
+func (l *Logger) Infof(format string, args ...interface{})    { l.l.Infof(format, args...) }
+func (l *Logger) Printf(format string, args ...interface{})   { l.l.Infof(format, args...) }
+func (l *Logger) Warnf(format string, args ...interface{})    { l.l.Warnf(format, args...) }
+func (l *Logger) Warningf(format string, args ...interface{}) { l.l.Warnf(format, args...) }
+func (l *Logger) Errorf(format string, args ...interface{})   { l.l.Errorf(format, args...) }
+func (l *Logger) Fatalf(format string, args ...interface{})   { l.l.Fatalf(format, args...) }
+func (l *Logger) Panicf(format string, args ...interface{})   { l.l.Panicf(format, args...) }
+
+func (l *Logger) Traceln(args ...interface{}) { l.l.Debug(sprintln(args)) }
+func (l *Logger) Debugln(args ...interface{}) { l.l.Debug(sprintln(args)) }
+func (l *Logger) Infoln(args ...interface{})  { l.l.Info(sprintln(args)) }
+func (l *Logger) Println(args ...interface{}) { l.l.Info(sprintln(args)) }
+func (l *Logger) Warnln(args ...interface{})  { l.l.Warn(sprintln(args)) }
+func (l *Logger) Errorln(args ...interface{}) { l.l.Error(sprintln(args)) }
+
+// Entry is a logger with fields, returned by WithField and WithFields.
+type Entry struct {
+	l *logger.Logger
+}
+
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{l: e.l.WithFields(Fields{key: value})}
+}
+
+func (e *Entry) WithFields(fields Fields) *Entry {
+	return &Entry{l: e.l.WithFields(fields)}
+}
+
+func (e *Entry) WithError(err error) *Entry {
+	return &Entry{l: e.l.WithFields(Fields{ErrorKey: err})}
+}
+
+func (e *Entry) Trace(args ...interface{})   { e.l.Debug(args...) }
+func (e *Entry) Debug(args ...interface{})   { e.l.Debug(args...) }
+func (e *Entry) Info(args ...interface{})    { e.l.Info(args...) }
+func (e *Entry) Print(args ...interface{})   { e.l.Info(args...) }
+func (e *Entry) Warn(args ...interface{})    { e.l.Warn(args...) }
+func (e *Entry) Warning(args ...interface{}) { e.l.Warn(args...) }
+func (e *Entry) Error(args ...interface{})   { e.l.Error(args...) }
+func (e *Entry) Fatal(args ...interface{})   { e.l.Fatal(args...) }
+func (e *Entry) Panic(args ...interface{})   { e.l.Panic(args...) }
+
+func (e *Entry) Tracef(format string, args ...interface{})   { e.l.Debugf(format, args...) }
+func (e *Entry) Debugf(format string, args ...interface{})   { e.l.Debugf(format, args...) }
+func (e *Entry) Infof(format string, args ...interface{})    { e.l.Infof(format, args...) }
+func (e *Entry) Printf(format string, args ...interface{})   { e.l.Infof(format, args...) }
+func (e *Entry) Warnf(format string, args ...interface{})    { e.l.Warnf(format, args...) }
+func (e *Entry) Warningf(format string, args ...interface{}) { e.l.Warnf(format, args...) }
+func (e *Entry) Errorf(format string, args ...interface{})   { e.l.Errorf(format, args...) }
+func (e *Entry) Fatalf(format string, args ...interface{})   { e.l.Fatalf(format, args...) }
+func (e *Entry) Panicf(format string, args ...interface{})   { e.l.Panicf(format, args...) }
+
+// sprintln joins args with spaces like fmt.Sprintln, without the newline.
+func sprintln(args []interface{}) string {
+	s := fmt.Sprintln(args...)
+	return s[:len(s)-1]
+}
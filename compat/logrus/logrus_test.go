@@ -0,0 +1,34 @@
+package logrus_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+	log "github.com/pecet3/logger/compat/logrus"
+)
+
+func TestEntry(t *testing.T) {
+	var out bytes.Buffer
+	l := log.Wrap(logger.New(&logger.Config{Output: &out, Format: logger.FormatJSON}))
+	l.SetLevel(log.InfoLevel)
+	l.Debug("hidden")
+	l.WithFields(log.Fields{"user": 7}).WithError(errors.New("timeout")).Errorf("login failed after %d tries", 3)
+
+	got := out.String()
+	for _, want := range []string{
+		`"level":"error"`,
+		`"msg":"login failed after 3 tries"`,
+		`"fields":{"user":7,"error":"timeout"}`,
+		`"function":"github.com/pecet3/logger/compat/logrus_test.TestEntry"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%s does not contain %s", got, want)
+		}
+	}
+	if strings.Contains(got, "hidden") {
+		t.Error("SetLevel should hide Debug entries")
+	}
+}
@@ -0,0 +1,45 @@
+package logrus
+
+import "github.com/pecet3/logger"
+
+// std is the logger of the package-level functions.
+var std = New()
+
+// StandardLogger returns the logger of the package-level functions.
+func StandardLogger() *Logger {
+	return std
+}
+
+// SetLogger makes the package-level functions write through l. Call it
+// at startup, before logging.
+func SetLogger(l *logger.Logger) {
+	std = Wrap(l)
+}
+
+func SetLevel(lv Level) { std.SetLevel(lv) }
+
+func WithField(key string, value interface{}) *Entry { return std.WithField(key, value) }
+func WithFields(fields Fields) *Entry                { return std.WithFields(fields) }
+func WithError(err error) *Entry                     { return std.WithError(err) }
+
+func Trace(args ...interface{})   { std.l.Debug(args...) }
+func Debug(args ...interface{})   { std.l.Debug(args...) }
+func Info(args ...interface{})    { std.l.Info(args...) }
+func Print(args ...interface{})   { std.l.Info(args...) }
+func Warn(args ...interface{})    { std.l.Warn(args...) }
+func Warning(args ...interface{}) { std.l.Warn(args...) }
+func Error(args ...interface{})   { std.l.Error(args...) }
+func Fatal(args ...interface{})   { std.l.Fatal(args...) }
+func Panic(args ...interface{})   { std.l.Panic(args...) }
+
+func Tracef(format string, args ...interface{})   { std.l.Debugf(format, args...) }
+func Debugf(format string, args ...interface{})   { std.l.Debugf(format, args...) }
+func Infof(format string, args ...interface{})    { std.l.Infof(format, args...) }
+func Printf(format string, args ...interface{})   { std.l.Infof(format, args...) }
+func Warnf(format string, args ...interface{})    { std.l.Warnf(format, args...) }
+func Warningf(format string, args ...interface{}) { std.l.Warnf(format, args...) }
+func Errorf(format string, args ...interface{})   { std.l.Errorf(format, args...) }
+func Fatalf(format string, args ...interface{})   { std.l.Fatalf(format, args...) }
+func Panicf(format string, args ...interface{})   { std.l.Panicf(format, args...) }
+
+func Println(args ...interface{}) { std.l.Info(sprintln(args)) }
@@ -0,0 +1,48 @@
+package zap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pecet3/logger"
+)
+
+// Field is a typed key-value pair, built with the functions below.
+type Field = logger.Field
+
+func Any(key string, value interface{}) Field { return Field{Key: key, Value: value} }
+func Bool(key string, value bool) Field       { return Field{Key: key, Value: value} }
+func Int(key string, value int) Field         { return Field{Key: key, Value: value} }
+func Int64(key string, value int64) Field     { return Field{Key: key, Value: value} }
+func Int32(key string, value int32) Field     { return Field{Key: key, Value: value} }
+func Uint(key string, value uint) Field       { return Field{Key: key, Value: value} }
+func Uint64(key string, value uint64) Field   { return Field{Key: key, Value: value} }
+func Float64(key string, value float64) Field { return Field{Key: key, Value: value} }
+func String(key string, value string) Field   { return Field{Key: key, Value: value} }
+func Strings(key string, value []string) Field {
+	return Field{Key: key, Value: value}
+}
+func Duration(key string, value time.Duration) Field { return Field{Key: key, Value: value} }
+func Time(key string, value time.Time) Field         { return Field{Key: key, Value: value} }
+
+// Stringer logs the String method of value.
+func Stringer(key string, value fmt.Stringer) Field {
+	return Field{Key: key, Value: value.String()}
+}
+
+// Error logs err under "error", nothing when it is nil.
+func Error(err error) Field {
+	return NamedError("error", err)
+}
+
+func NamedError(key string, err error) Field {
+	if err == nil {
+		return Skip()
+	}
+	return Field{Key: key, Value: err}
+}
+
+// Skip is a field that is not logged.
+func Skip() Field {
+	return Field{}
+}
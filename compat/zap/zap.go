@@ -0,0 +1,190 @@
+// Package zap exposes the zap API on top of a logger.Logger, so code
+// written for go.uber.org/zap can switch by changing its import:
+//
+//	import "github.com/pecet3/logger/compat/zap"
+//
+//	log, _ := zap.NewProduction()
+//	log.Info("request served", zap.String("path", path), zap.Int("status", 200))
+//	log.Sugar().Infow("request served", "path", path, "status", 200)
+//
+// Only the logging methods are provided; cores, encoders and sampling are
+// configured on the wrapped logger.
+package zap
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pecet3/logger"
+)
+
+// Logger is a zap logger writing through a logger.Logger.
+type Logger struct {
+	l *logger.Logger
+}
+
+// Wrap returns a zap logger writing through l.
+func Wrap(l *logger.Logger) *Logger {
+	return &Logger{l: l.CallerSkip(1)}
+}
+
+// NewProduction returns a logger writing JSON entries of Info and above to
+// stderr.
+func NewProduction() (*Logger, error) {
+	return Wrap(logger.New(&logger.Config{Format: logger.FormatJSON, Output: os.Stderr, Level: logger.LevelInfo})), nil
+}
+
+// NewDevelopment returns a logger writing console entries of every level to
+// stderr.
+func NewDevelopment() (*Logger, error) {
+	return Wrap(logger.New(&logger.Config{Output: os.Stderr})), nil
+}
+
+// NewExample returns a logger writing JSON entries to stdout.
+func NewExample() *Logger {
+	return Wrap(logger.New(&logger.Config{Format: logger.FormatJSON}))
+}
+
+// NewNop returns a logger discarding everything.
+func NewNop() *Logger {
+	return Wrap(logger.Nop())
+}
+
+// Unwrap returns the logger written through.
+func (z *Logger) Unwrap() *logger.Logger {
+	return z.l.CallerSkip(-1)
+}
+
+// With returns a logger attaching fields to every entry.
+func (z *Logger) With(fields ...Field) *Logger {
+	return &Logger{l: z.l.With(marshalers(fields)...)}
+}
+
+func (z *Logger) Named(name string) *Logger {
+	return &Logger{l: z.l.Named(name)}
+}
+
+func (z *Logger) Sugar() *SugaredLogger {
+	return &SugaredLogger{l: z.l}
+}
+
+// Sync flushes the sinks of the wrapped logger.
+func (z *Logger) Sync() error {
+	return z.l.Sync()
+}
+
+func (z *Logger) Debug(msg string, fields ...Field) { z.l.Debug(args(msg, fields)...) }
+func (z *Logger) Info(msg string, fields ...Field)  { z.l.Info(args(msg, fields)...) }
+func (z *Logger) Warn(msg string, fields ...Field)  { z.l.Warn(args(msg, fields)...) }
+func (z *Logger) Error(msg string, fields ...Field) { z.l.Error(args(msg, fields)...) }
+
+// DPanic logs at Error level; it never panics.
+func (z *Logger) DPanic(msg string, fields ...Field) { z.l.Error(args(msg, fields)...) }
+func (z *Logger) Panic(msg string, fields ...Field)  { z.l.Panic(args(msg, fields)...) }
+func (z *Logger) Fatal(msg string, fields ...Field)  { z.l.Fatal(args(msg, fields)...) }
+
+func args(msg string, fields []Field) []interface{} {
+	out := make([]interface{}, 0, len(fields)+1)
+	out = append(out, msg)
+	for _, f := range fields {
+		if f.Key != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func marshalers(fields []Field) []logger.LogMarshaler {
+	out := make([]logger.LogMarshaler, 0, len(fields))
+	for _, f := range fields {
+		if f.Key != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// SugaredLogger is the loosely typed zap API, returned by Logger.Sugar.
+type SugaredLogger struct {
+	l *logger.Logger
+}
+
+// Desugar returns the typed logger.
+func (s *SugaredLogger) Desugar() *Logger {
+	return &Logger{l: s.l}
+}
+
+// With returns a logger attaching the key-value pairs to every entry.
+func (s *SugaredLogger) With(keysAndValues ...interface{}) *SugaredLogger {
+	return &SugaredLogger{l: s.l.With(marshalers(sweeten(keysAndValues))...)}
+}
+
+func (s *SugaredLogger) Named(name string) *SugaredLogger {
+	return &SugaredLogger{l: s.l.Named(name)}
+}
+
+func (s *SugaredLogger) Sync() error {
+	return s.l.Sync()
+}
+
+func (s *SugaredLogger) Debug(args ...interface{}) { s.l.Debug(args...) }
+func (s *SugaredLogger) Info(args ...interface{})  { s.l.Info(args...) }
+func (s *SugaredLogger) Warn(args ...interface{})  { s.l.Warn(args...) }
+func (s *SugaredLogger) Error(args ...interface{}) { s.l.Error(args...) }
+func (s *SugaredLogger) Panic(args ...interface{}) { s.l.Panic(args...) }
+func (s *SugaredLogger) Fatal(args ...interface{}) { s.l.Fatal(args...) }
+
+func (s *SugaredLogger) Debugf(template string, args ...interface{}) { s.l.Debugf(template, args...) }
+func (s *SugaredLogger) Infof(template string, args ...interface{})  { s.l.Infof(template, args...) }
+func (s *SugaredLogger) Warnf(template string, args ...interface{})  { s.l.Warnf(template, args...) }
+func (s *SugaredLogger) Errorf(template string, args ...interface{}) { s.l.Errorf(template, args...) }
+func (s *SugaredLogger) Panicf(template string, args ...interface{}) { s.l.Panicf(template, args...) }
+func (s *SugaredLogger) Fatalf(template string, args ...interface{}) { s.l.Fatalf(template, args...) }
+
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	s.l.Debug(args(msg, sweeten(keysAndValues))...)
+}
+
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{}) {
+	s.l.Info(args(msg, sweeten(keysAndValues))...)
+}
+
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	s.l.Warn(args(msg, sweeten(keysAndValues))...)
+}
+
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	s.l.Error(args(msg, sweeten(keysAndValues))...)
+}
+
+func (s *SugaredLogger) Panicw(msg string, keysAndValues ...interface{}) {
+	s.l.Panic(args(msg, sweeten(keysAndValues))...)
+}
+
+func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...interface{}) {
+	s.l.Fatal(args(msg, sweeten(keysAndValues))...)
+}
+
+// sweeten turns alternating keys and values into fields. Fields are taken
+// as they are, and a value without a key is kept under "ignored", as zap
+// does.
+func sweeten(keysAndValues []interface{}) []Field {
+	var fields []Field
+	for i := 0; i < len(keysAndValues); i++ {
+		if f, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		if i == len(keysAndValues)-1 {
+			fields = append(fields, Any("ignored", keysAndValues[i]))
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields = append(fields, Any(key, keysAndValues[i+1]))
+		i++
+	}
+	return fields
+}
@@ -0,0 +1,33 @@
+package zap_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+	"github.com/pecet3/logger/compat/zap"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := zap.Wrap(logger.New(&logger.Config{Output: &out, Format: logger.FormatJSON}))
+	l.Named("api").With(zap.String("region", "eu")).Error("request failed",
+		zap.Int("status", 502), zap.Error(errors.New("upstream")), zap.Error(nil))
+	l.Sugar().Infow("served", "path", "/items", "status", 200, "dangling")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	for _, want := range []string{
+		`"logger":"api"`,
+		`"fields":{"region":"eu","status":502,"error":"upstream"}`,
+		`"function":"github.com/pecet3/logger/compat/zap_test.TestLogger"`,
+	} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("%s does not contain %s", lines[0], want)
+		}
+	}
+	if want := `"fields":{"path":"/items","status":200,"ignored":"dangling"}`; !strings.Contains(lines[1], want) {
+		t.Errorf("%s does not contain %s", lines[1], want)
+	}
+}
@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var defaultBadges = map[Level]string{
+	LevelDebug: " DBUG ",
+	LevelInfo:  " INFO ",
+	LevelWarn:  " WARN ",
+	LevelError: " ERROR",
+	LevelAlert: " ALERT",
+	LevelPanic: " PANIC",
+	LevelFatal: " FATAL",
+}
+
+var levelIcons = map[Level]string{
+	LevelDebug: "⚙",
+	LevelInfo:  "ℹ",
+	LevelWarn:  "⚠",
+	LevelError: "✖",
+	LevelAlert: "⚡",
+	LevelPanic: "💥",
+	LevelFatal: "☠",
+}
+
+var levelIconsASCII = map[Level]string{
+	LevelDebug: ".",
+	LevelInfo:  "i",
+	LevelWarn:  "!",
+	LevelError: "x",
+	LevelAlert: "*",
+	LevelPanic: "@",
+	LevelFatal: "#",
+}
+
+// processStart is the reference point of the uptime column.
+var processStart = time.Now()
+
+type Uptime int
+
+const (
+	UptimeOff Uptime = iota
+	// UptimeAlongside prints seconds since process start after the time.
+	UptimeAlongside
+	// UptimeOnly prints seconds since process start instead of date and time.
+	UptimeOnly
+)
+
+type formatter struct {
+	theme      *Theme
+	uptime     Uptime
+	badges     map[Level]string
+	badgeWidth int
+	icons      map[Level]string
+	markers    map[Level]string
+	inline     map[Level]bool
+	singleLine bool
+	custom     Formatter // replaces the console layout when set
+}
+
+var defaultFormatter = &formatter{theme: DefaultTheme()}
+
+// newFormatter returns the console formatter of c for entries written to w.
+func newFormatter(c *Config, w io.Writer) *formatter {
+	f := &formatter{
+		theme:      c.Theme,
+		uptime:     c.Uptime,
+		badges:     c.Badges,
+		badgeWidth: c.BadgeWidth,
+		markers:    c.Markers,
+		inline:     c.Inline,
+		singleLine: c.SingleLine,
+	}
+	if c.Icons {
+		f.icons = levelIcons
+		if !unicodeSupported() {
+			f.icons = levelIconsASCII
+		}
+	}
+	term := outputTerminal(w)
+	if f.theme == nil {
+		f.theme = DefaultTheme()
+		if term.Light {
+			f.theme = LightTheme()
+		}
+		if c.Icons {
+			f.theme = iconTheme(f.theme)
+		}
+	}
+	switch {
+	case c.Color == ColorNever,
+		c.Color == ColorAuto && (!term.IsTerminal || term.Colors == ColorNone):
+		f.theme = &Theme{}
+	case term.IsTerminal:
+		// 24-bit and 256-color styles fall back to the closest color the
+		// terminal has.
+		f.theme = f.theme.Downsample(term.Colors)
+	}
+	f.custom = c.Formatter
+	if f.custom == nil && c.Template != "" {
+		// New reports templates that do not parse.
+		f.custom, _ = ParseTemplate(c.Template)
+	}
+	if tf, ok := f.custom.(*templateFormatter); ok {
+		f.custom = tf.withFormatter(f)
+	}
+	return f
+}
+
+func (f *formatter) badge(lv Level) string {
+	badge, ok := f.badges[lv]
+	if !ok {
+		badge = defaultBadges[lv]
+	}
+	if f.badgeWidth <= 0 {
+		return badge
+	}
+	if n := displayWidth(badge); n <= f.badgeWidth {
+		return badge + strings.Repeat(" ", f.badgeWidth-n)
+	}
+	// A wide character that does not fit is replaced by padding.
+	cut, n := truncateWidth(badge, f.badgeWidth)
+	return cut + strings.Repeat(" ", f.badgeWidth-n)
+}
+
+func (f *formatter) console(e Entry) string {
+	t := f.theme
+	badge := (t.Badge + t.Levels[e.level]).render(f.badge(e.level))
+	clock := t.Date.render(formatDate(e.time)) + " " + t.Time.render(formatTime(e.time))
+	if f.uptime != UptimeOff {
+		up := t.Uptime.render(fmt.Sprintf("%9.3fs", e.time.Sub(processStart).Seconds()))
+		if f.uptime == UptimeOnly {
+			clock = up
+		} else {
+			clock += " " + up
+		}
+	}
+	if e.name != "" {
+		clock += " " + t.name(e.name).render(e.name)
+	}
+	for _, tag := range e.tags {
+		clock += " " + tagChip(t.tag(tag), tag)
+	}
+
+	prefix := ""
+	if icon, ok := f.icons[e.level]; ok {
+		prefix = t.Levels[e.level].render(icon) + " "
+	}
+	caller := e.Caller()
+	if caller.IsZero() {
+		return fmt.Sprintf(`%s[%s] %s %s`,
+			prefix,
+			badge,
+			clock,
+			joinFields(t.message(e.level, false).render(e.message), e.fields, t),
+		)
+	}
+	content := fmt.Sprintf(`%s[%s] %s (%s:%s)`,
+		prefix,
+		badge,
+		clock,
+		t.Caller.render(caller.Function),
+		t.Line.render(strconv.Itoa(caller.Line)),
+	)
+	if e.message != "" || len(e.fields) > 0 {
+		msg := ""
+		if e.message != "" {
+			msg = t.message(e.level, true).render(e.message)
+		}
+		if f.singleLine || f.inline[e.level] {
+			content += " " + joinFields(msg, e.fields, t)
+		} else {
+			content += "\n" + f.marker(e.level) + joinFields(msg, e.fields, t)
+		}
+	}
+	return content
+}
+
+// marker returns the styled continuation marker of lv followed by a space,
+// or nothing when the marker is disabled.
+func (f *formatter) marker(lv Level) string {
+	m, ok := f.markers[lv]
+	if !ok {
+		m = "↳"
+	}
+	if m == "" {
+		return ""
+	}
+	return f.theme.Markers[lv].render(m) + " "
+}
+
+// tagChip renders a tag on a colored background, or as "#tag" when
+// unstyled.
+func tagChip(style Style, tag string) string {
+	if style == "" || !ansiSupported {
+		return "#" + tag
+	}
+	return style.render(" " + tag + " ")
+}
+
+// joinFields appends the rendered fields to msg, styled with t when it is
+// not nil. Stack traces and dumps follow on their own lines.
+func joinFields(msg string, fields []Field, t *Theme) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	if t == nil {
+		t = &Theme{}
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	appendFields(&b, "", fields, t.FieldKey.render)
+	b.WriteString(blockLines(fields, t))
+	return b.String()
+}
+
+func (f *formatter) plain(e Entry) string {
+	badge := f.badge(e.level)
+	clock := formatTime(e.time)
+	if e.name != "" {
+		clock += " " + e.name
+	}
+	for _, tag := range e.tags {
+		clock += " " + tagChip("", tag)
+	}
+	caller := e.Caller()
+	if caller.IsZero() {
+		return fmt.Sprintf(`[%s] %s %s  %s`,
+			badge,
+			formatDate(e.time),
+			clock,
+			joinFields(e.message, e.fields, nil),
+		)
+	}
+	return fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
+		badge,
+		formatDate(e.time),
+		clock,
+		caller.Function,
+		strconv.Itoa(caller.Line),
+		joinFields(e.message, e.fields, nil),
+	)
+}
+
+// stdoutFormatter renders the entries of the package-level functions when
+// no default logger is installed.
+var stdoutFormatter = sync.OnceValue(func() *formatter {
+	return newFormatter(&Config{}, os.Stdout)
+})
@@ -0,0 +1,79 @@
+//go:build js && wasm
+
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"syscall/js"
+	"time"
+)
+
+// BrowserConsole is a Sink writing entries to the browser console with the
+// method matching their level. Fields, caller and logger name are passed as
+// an object, so they can be expanded in the developer tools.
+type BrowserConsole struct{}
+
+var consoleMethods = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "log",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelAlert: "error",
+	LevelPanic: "error",
+	LevelFatal: "error",
+}
+
+func (BrowserConsole) WriteEntry(e Entry) error {
+	console := js.Global().Get("console")
+	if console.IsUndefined() {
+		return fmt.Errorf("browser console: console is not defined")
+	}
+	method, ok := consoleMethods[e.level]
+	if !ok {
+		method = "log"
+	}
+
+	obj := jsObject(e.fields)
+	if e.name != "" {
+		obj.Set("logger", e.name)
+	}
+	caller := e.Caller()
+	if !caller.IsZero() {
+		obj.Set("caller", caller.Function+":"+strconv.Itoa(caller.Line))
+	}
+	obj.Set("time", e.time.Format(time.RFC3339Nano))
+	console.Call(method, e.message, obj)
+	return nil
+}
+
+func jsObject(fields []Field) js.Value {
+	obj := js.Global().Get("Object").New()
+	for _, f := range fields {
+		obj.Set(f.Key, jsValue(f.Value))
+	}
+	return obj
+}
+
+func jsValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case []Field:
+		return jsObject(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = jsValue(item)
+		}
+		return list
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// hooks are the functions registered with AddContextHook and AddHook,
+// shared by the loggers derived from a logger.
+type hooks struct {
+	mu    sync.RWMutex
+	ctx   []func(ctx context.Context) []Field
+	entry []levelHook
+}
+
+// AddContextHook registers fn to extract fields from the context of every
+// Ctx call, e.g. the request, trace or user ID a middleware stored in it:
+//
+//	l.AddContextHook(func(ctx context.Context) []logger.Field {
+//		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+//			return []logger.Field{{Key: "request_id", Value: id}}
+//		}
+//		return nil
+//	})
+//
+// The hook applies to l and to the loggers derived from it with Tag or
+// WithFields, before and after it was added.
+func (l *Logger) AddContextHook(fn func(ctx context.Context) []Field) {
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.ctx = append(l.hooks.ctx, fn)
+}
+
+func (l *Logger) logCtx(ctx context.Context, e Entry) {
+	if ctx != nil {
+		l.hooks.mu.RLock()
+		var fields []Field
+		for _, fn := range l.hooks.ctx {
+			fields = append(fields, fn(ctx)...)
+		}
+		l.hooks.mu.RUnlock()
+		if len(fields) > 0 {
+			e.fields = append(fields, e.fields...)
+		}
+	}
+	l.log(e)
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, args ...interface{}) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.logCtx(ctx, newEntry(LevelError, args, l.caller(LevelError)))
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, args ...interface{}) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.logCtx(ctx, newEntry(LevelInfo, args, nil))
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, args ...interface{}) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.logCtx(ctx, newEntry(LevelWarn, args, nil))
+}
+
+func (l *Logger) DebugCtx(ctx context.Context, args ...interface{}) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.logCtx(ctx, newEntry(LevelDebug, args, l.caller(LevelDebug)))
+}
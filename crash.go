@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	runtimedebug "runtime/debug"
+	"strings"
+)
+
+// Recover logs a panic unwinding the calling goroutine as a Fatal entry
+// with the panic value and stack trace, flushes the sinks and panics again
+// with the same value, so the crash reaches file and network sinks before
+// the runtime prints it and exits. Defer it at the top of main and of
+// long-running goroutines:
+//
+//	defer log.Recover()
+func (l *Logger) Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if l.enabled(LevelFatal) {
+		fields := []Field{
+			{Key: "panic", Value: panicString(r)},
+			{Key: "stack", Value: stackTrace(strings.TrimSpace(string(runtimedebug.Stack())))},
+		}
+		l.log(makeEntry(LevelFatal, "unrecovered panic", fields, nil))
+	}
+	l.flushSinks()
+	panic(r)
+}
+
+func panicString(r interface{}) string {
+	if err, ok := r.(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(r)
+}
+
+// CrashReports makes the runtime write the report of a crash nothing can
+// recover from, such as a panic in a goroutine without Recover or a fatal
+// error, to the file at path besides stderr (see debug.SetCrashOutput).
+// A report left there by an earlier run is logged as a Fatal entry first
+// and the file emptied, so the next start after a crash brings it to the
+// sinks. Call it once, early in main.
+func (l *Logger) CrashReports(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading crash report: %w", err)
+	}
+	if report := bytes.TrimSpace(data); len(report) > 0 && l.enabled(LevelFatal) {
+		fields := crashFields(string(report))
+		if info, err := os.Stat(path); err == nil {
+			fields = append(fields, Field{Key: "crashed_at", Value: info.ModTime()})
+		}
+		l.log(makeEntry(LevelFatal, "previous run crashed", fields, nil))
+		l.flushSinks()
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating crash report: %w", err)
+	}
+	defer f.Close()
+	if err := runtimedebug.SetCrashOutput(f, runtimedebug.CrashOptions{}); err != nil {
+		return fmt.Errorf("setting crash output: %w", err)
+	}
+	return nil
+}
+
+// crashFields splits a crash report into the panic message or fatal error
+// on its first line and the goroutine stacks below it.
+func crashFields(report string) []Field {
+	first, rest, _ := strings.Cut(report, "\n")
+	for _, prefix := range []string{"panic: ", "fatal error: "} {
+		if msg, ok := strings.CutPrefix(first, prefix); ok {
+			return []Field{
+				{Key: "panic", Value: msg},
+				{Key: "stack", Value: stackTrace(strings.TrimSpace(rest))},
+			}
+		}
+	}
+	return []Field{{Key: "stack", Value: stackTrace(report)}}
+}
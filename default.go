@@ -0,0 +1,53 @@
+package logger
+
+import "sync/atomic"
+
+var (
+	defaultLogger atomic.Pointer[Logger]
+	// fallbackLevel is the lowest level written by the package-level
+	// functions while no default logger is installed.
+	fallbackLevel atomic.Int32
+)
+
+// SetDefault installs l as the logger used by the package-level functions.
+// With nil they write to stdout again, at the level set with SetLevel.
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// Default returns the logger installed with SetDefault, or nil.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetLevel changes the lowest level logged by the package-level functions:
+// that of the default logger, or of the stdout output used without one.
+func SetLevel(lv Level) {
+	if l := Default(); l != nil {
+		l.SetLevel(lv)
+		return
+	}
+	fallbackLevel.Store(int32(lv))
+}
+
+func GetLevel() Level {
+	if l := Default(); l != nil {
+		return l.GetLevel()
+	}
+	return Level(fallbackLevel.Load())
+}
+
+// Enabled reports whether the package-level functions log entries of the
+// given level.
+func Enabled(lv Level) bool {
+	return defaultEnabled(lv)
+}
+
+// Sync waits until the default logger has written everything logged so far,
+// see Logger.Sync.
+func Sync() error {
+	if l := Default(); l != nil {
+		return l.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Message dictionaries store repetitive messages as a template defined once
+// per file and referenced by every entry using it, with the variable words
+// (those containing digits) as parameters:
+//
+//	{"def":1,"v":2,"text":"user \u001a logged in after \u001a"}
+//	{"v":2,"time":...,"level":"info","msg":"","tmpl":1,"params":["42","1.2s"]}
+//
+// Definitions may be repeated with the same ID, e.g. when a process appends
+// to a file written by a previous one; the latest one applies. Both lines
+// carry schema version 2, so readers without dictionaries reject them
+// instead of decoding empty messages.
+
+// templateParam marks the parameters in a template. Messages containing it
+// are written as they are.
+const templateParam = "\x1a"
+
+// maxTemplates bounds the memory of a dictionary; messages past it are
+// written as they are.
+const maxTemplates = 4096
+
+type templateDef struct {
+	ID      uint32 `json:"def"`
+	Version int    `json:"v"`
+	Text    string `json:"text"`
+}
+
+var defPrefix = []byte(`{"def":`)
+
+// splitTemplate returns msg with its variable words replaced by
+// templateParam, and the words.
+func splitTemplate(msg string) (string, []string) {
+	var b strings.Builder
+	var params []string
+	for len(msg) > 0 {
+		i := strings.IndexFunc(msg, unicode.IsSpace)
+		if i < 0 {
+			i = len(msg)
+		}
+		word := msg[:i]
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			b.WriteString(templateParam)
+			params = append(params, word)
+		} else {
+			b.WriteString(word)
+		}
+		msg = msg[i:]
+		j := strings.IndexFunc(msg, func(r rune) bool { return !unicode.IsSpace(r) })
+		if j < 0 {
+			j = len(msg)
+		}
+		b.WriteString(msg[:j])
+		msg = msg[j:]
+	}
+	return b.String(), params
+}
+
+func joinTemplate(tmpl string, params []string) (string, error) {
+	parts := strings.Split(tmpl, templateParam)
+	if len(parts) != len(params)+1 {
+		return "", fmt.Errorf("template expects %d params, got %d", len(parts)-1, len(params))
+	}
+	var b strings.Builder
+	for i, p := range parts {
+		if i > 0 {
+			b.WriteString(params[i-1])
+		}
+		b.WriteString(p)
+	}
+	return b.String(), nil
+}
+
+type dictEncoder struct {
+	ids map[string]uint32
+}
+
+// appendEntry appends the NDJSON line of e to buf, preceded by the
+// definition of its template the first time it is used.
+func (d *dictEncoder) appendEntry(buf []byte, e Entry) ([]byte, error) {
+	if d.ids == nil {
+		d.ids = make(map[string]uint32)
+	}
+	var (
+		id     uint32
+		params []string
+	)
+	if !strings.Contains(e.message, templateParam) {
+		var tmpl string
+		tmpl, params = splitTemplate(e.message)
+		var ok bool
+		if id, ok = d.ids[tmpl]; !ok && len(d.ids) < maxTemplates {
+			id = uint32(len(d.ids) + 1)
+			d.ids[tmpl] = id
+			b, err := json.Marshal(templateDef{ID: id, Version: 2, Text: tmpl})
+			if err != nil {
+				return buf, err
+			}
+			buf = append(append(buf, b...), '\n')
+		}
+	}
+	b, err := e.marshalJSON(id, params)
+	if err != nil {
+		return buf, err
+	}
+	return append(append(buf, b...), '\n'), nil
+}
+
+type dictDecoder struct {
+	templates map[uint32]string
+}
+
+// decode parses an NDJSON line. ok is false for template definitions, which
+// are remembered for the following entries.
+func (d *dictDecoder) decode(line []byte) (e Entry, ok bool, err error) {
+	if bytes.HasPrefix(line, defPrefix) {
+		var def templateDef
+		if err := json.Unmarshal(line, &def); err != nil {
+			return Entry{}, false, err
+		}
+		if d.templates == nil {
+			d.templates = make(map[uint32]string)
+		}
+		d.templates[def.ID] = def.Text
+		return Entry{}, false, nil
+	}
+	je, err := e.unmarshalJSON(line)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if je.Template != 0 {
+		tmpl, found := d.templates[je.Template]
+		if !found {
+			return Entry{}, false, fmt.Errorf("unknown message template %d", je.Template)
+		}
+		if e.message, err = joinTemplate(tmpl, je.Params); err != nil {
+			return Entry{}, false, err
+		}
+	}
+	return e, true, nil
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDumpDepth bounds how deep Dump follows nested values.
+const maxDumpDepth = 10
+
+// dumpValue is a value printed as an indented tree below the console entry.
+type dumpValue struct {
+	v interface{}
+}
+
+func (d dumpValue) String() string {
+	return dump(d.v, nil)
+}
+
+func (d dumpValue) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(d.v)
+	if err != nil {
+		return json.Marshal(d.String())
+	}
+	return b, nil
+}
+
+// Pretty returns a field printing v as an indented tree of its struct
+// fields, map entries and slice elements below the console entry. JSON
+// output gets v itself.
+func Pretty(v interface{}) Field {
+	return Field{Key: "value", Value: dumpValue{v}}
+}
+
+// Dump logs v at LevelDebug as an indented, colored tree, with its type as
+// the message.
+func (l *Logger) Dump(v interface{}) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.log(makeEntry(LevelDebug, dumpType(v), []Field{Pretty(v)}, l.caller(LevelDebug)))
+}
+
+func dumpType(v interface{}) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return reflect.TypeOf(v).String()
+}
+
+// dump renders v with the dump styles of t, unstyled when t is nil.
+func dump(v interface{}, t *Theme) string {
+	if t == nil {
+		t = &Theme{}
+	}
+	d := dumper{t: t, seen: make(map[uintptr]bool)}
+	d.value(reflect.ValueOf(v), "", 0)
+	return d.b.String()
+}
+
+type dumper struct {
+	b    strings.Builder
+	t    *Theme
+	seen map[uintptr]bool // pointers on the current path
+}
+
+func (d *dumper) value(v reflect.Value, indent string, depth int) {
+	if !v.IsValid() {
+		d.b.WriteString(d.t.DumpLiteral.render("<nil>"))
+		return
+	}
+	if v.CanInterface() && v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+		switch s := v.Interface().(type) {
+		case error:
+			d.b.WriteString(d.t.DumpString.render(strconv.Quote(s.Error())))
+			return
+		case fmt.Stringer:
+			d.b.WriteString(d.t.DumpString.render(s.String()))
+			return
+		}
+	}
+	typ := d.t.DumpType.render(v.Type().String())
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.b.WriteString("(" + typ + ")(" + d.t.DumpLiteral.render("nil") + ")")
+			return
+		}
+		if d.seen[v.Pointer()] {
+			d.b.WriteString(d.t.DumpLiteral.render("<cycle>"))
+			return
+		}
+		d.seen[v.Pointer()] = true
+		d.b.WriteByte('&')
+		d.value(v.Elem(), indent, depth)
+		delete(d.seen, v.Pointer())
+	case reflect.Interface:
+		if v.IsNil() {
+			d.b.WriteString(d.t.DumpLiteral.render("<nil>"))
+			return
+		}
+		d.value(v.Elem(), indent, depth)
+	case reflect.Struct:
+		d.block(typ, v.NumField(), indent, depth, func(i int, inner string) {
+			d.b.WriteString(d.t.FieldKey.render(v.Type().Field(i).Name) + ": ")
+			d.value(v.Field(i), inner, depth+1)
+		})
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		d.block(typ, len(keys), indent, depth, func(i int, inner string) {
+			d.value(keys[i], inner, depth+1)
+			d.b.WriteString(": ")
+			d.value(v.MapIndex(keys[i]), inner, depth+1)
+		})
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			d.b.WriteString(typ + "(" + d.t.DumpLiteral.render("nil") + ")")
+			return
+		}
+		d.block(typ, v.Len(), indent, depth, func(i int, inner string) {
+			d.value(v.Index(i), inner, depth+1)
+		})
+	case reflect.String:
+		d.b.WriteString(d.t.DumpString.render(strconv.Quote(v.String())))
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		d.b.WriteString(d.t.DumpLiteral.render(formatScalar(v)))
+	default:
+		// Channels, functions and unsafe pointers.
+		d.b.WriteString(typ)
+	}
+}
+
+// block writes "type{", one line per element and the closing brace.
+func (d *dumper) block(typ string, n int, indent string, depth int, elem func(i int, inner string)) {
+	d.b.WriteString(typ + "{")
+	if n == 0 {
+		d.b.WriteByte('}')
+		return
+	}
+	if depth >= maxDumpDepth {
+		d.b.WriteString("…}")
+		return
+	}
+	inner := indent + "  "
+	for i := 0; i < n; i++ {
+		d.b.WriteString("\n" + inner)
+		elem(i, inner)
+		d.b.WriteByte(',')
+	}
+	d.b.WriteString("\n" + indent + "}")
+}
+
+func formatScalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	}
+	return fmt.Sprint(v.Complex())
+}
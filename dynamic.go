@@ -0,0 +1,51 @@
+package logger
+
+import "sync"
+
+type dynamicField struct {
+	key   string
+	level Level
+	fn    func() interface{}
+}
+
+var (
+	dynamicMu     sync.RWMutex
+	dynamicFields []dynamicField
+)
+
+// RegisterDynamicField attaches the value returned by fn, evaluated when
+// the entry is logged, to every entry at minLevel or above. Registering a
+// key again replaces it.
+func RegisterDynamicField(key string, minLevel Level, fn func() interface{}) {
+	dynamicMu.Lock()
+	defer dynamicMu.Unlock()
+	for i := range dynamicFields {
+		if dynamicFields[i].key == key {
+			dynamicFields[i] = dynamicField{key: key, level: minLevel, fn: fn}
+			return
+		}
+	}
+	dynamicFields = append(dynamicFields, dynamicField{key: key, level: minLevel, fn: fn})
+}
+
+func UnregisterDynamicField(key string) {
+	dynamicMu.Lock()
+	defer dynamicMu.Unlock()
+	for i := range dynamicFields {
+		if dynamicFields[i].key == key {
+			dynamicFields = append(dynamicFields[:i], dynamicFields[i+1:]...)
+			return
+		}
+	}
+}
+
+func (e *Entry) addDynamicFields() {
+	dynamicMu.RLock()
+	defer dynamicMu.RUnlock()
+	for _, d := range dynamicFields {
+		if e.level < d.level {
+			continue
+		}
+		e.fields = append(e.fields[:len(e.fields):len(e.fields)], Field{Key: d.key, Value: d.fn()})
+	}
+}
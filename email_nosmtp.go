@@ -0,0 +1,20 @@
+//go:build logger_nosmtp
+
+package logger
+
+import (
+	"context"
+	"errors"
+)
+
+// errNoSMTP is returned by Email when the binary is built with the
+// logger_nosmtp tag, which leaves net/smtp out of it.
+var errNoSMTP = errors.New("email sending disabled by the logger_nosmtp build tag")
+
+func (e Email) SendLogs(ctx context.Context, l *Logger) error {
+	return errNoSMTP
+}
+
+func (e Email) SendAlert(ctx context.Context, l *Logger, content string) error {
+	return errNoSMTP
+}
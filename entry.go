@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelAlert
+	// LevelPanic is logged by Panic before it panics.
+	LevelPanic
+	// LevelFatal is for errors the process cannot recover from.
+	LevelFatal
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+	LevelAlert: "alert",
+	LevelPanic: "panic",
+	LevelFatal: "fatal",
+}
+
+func (lv Level) String() string {
+	if name, ok := levelNames[lv]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int(lv))
+}
+
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for lv, name := range levelNames {
+		if name == s {
+			return lv, nil
+		}
+	}
+	return LevelDebug, fmt.Errorf("unknown level: %q", s)
+}
+
+type Caller struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (c Caller) IsZero() bool {
+	return c.Function == "" && c.File == "" && c.Line == 0
+}
+
+// callerRef is the caller of an entry. Only the program counter is taken
+// while logging; the function, file and line are looked up on first use,
+// usually by the formatter.
+type callerRef struct {
+	pc   uintptr
+	once sync.Once
+	c    Caller
+}
+
+func (r *callerRef) get() Caller {
+	if r == nil {
+		return Caller{}
+	}
+	r.once.Do(func() {
+		if r.pc == 0 {
+			return
+		}
+		f, _ := runtime.CallersFrames([]uintptr{r.pc}).Next()
+		r.c = Caller{Function: f.Function, File: f.File, Line: f.Line}
+	})
+	return r.c
+}
+
+// captureCaller records the caller skip frames up, as for runtime.Caller.
+func captureCaller(skip int) *callerRef {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return nil
+	}
+	return &callerRef{pc: pcs[0]}
+}
+
+// caller captures the caller of the logging method calling it, unless lv
+// is below Config.CallerLevel.
+func (l *Logger) caller(lv Level) *callerRef {
+	if lv < l.c.CallerLevel {
+		return nil
+	}
+	return captureCaller(3 + l.skip)
+}
+
+// Entry is a single log record as passed to sinks and formatters. It is
+// immutable: the accessors return copies, so a sink can keep or hand out an
+// entry without other sinks seeing changes.
+type Entry struct {
+	time    time.Time
+	level   Level
+	message string
+	caller  *callerRef
+	fields  []Field
+	name    string
+	tags    []string
+	seq     uint64
+	skew    time.Duration
+	// args is set when the message was joined from the arguments of a
+	// method such as Info rather than formatted, to lint it.
+	args bool
+}
+
+var entrySeq atomic.Uint64
+
+func newEntry(level Level, args []interface{}, caller *callerRef) Entry {
+	message, fields := splitArgs(args)
+	e := makeEntry(level, message, fields, caller)
+	e.args = true
+	return e
+}
+
+func makeEntry(level Level, message string, fields []Field, caller *callerRef) Entry {
+	t, skew, offset := entryTime()
+	if offset != nil {
+		fields = append(fields, *offset)
+	}
+	if skew > 0 {
+		fields = append(fields, Field{Key: "clock_skew", Value: skew})
+	}
+	return Entry{
+		time:    t,
+		level:   level,
+		message: message,
+		caller:  caller,
+		fields:  fields,
+		seq:     entrySeq.Add(1),
+		skew:    skew,
+	}
+}
+
+func (e Entry) Time() time.Time {
+	return e.time
+}
+
+func (e Entry) Level() Level {
+	return e.level
+}
+
+func (e Entry) Message() string {
+	return e.message
+}
+
+// Caller returns the call site of the entry, or a zero Caller when it was
+// logged without context (e.g. Info, Warn).
+func (e Entry) Caller() Caller {
+	return e.caller.get()
+}
+
+// Fields returns a copy of the fields of the entry. Nested objects are
+// []Field values.
+func (e Entry) Fields() []Field {
+	return cloneFields(e.fields)
+}
+
+// Name is the name of the logger that produced the entry.
+func (e Entry) Name() string {
+	return e.name
+}
+
+// Tags are the labels attached with Logger.Tag.
+func (e Entry) Tags() []string {
+	return append([]string(nil), e.tags...)
+}
+
+// Seq is a process-wide, strictly increasing sequence number. Unlike Time it
+// keeps entries ordered when the wall clock is stepped back.
+func (e Entry) Seq() uint64 {
+	return e.seq
+}
+
+// Clone returns a deep copy of e, sharing nothing with it but field values
+// other than nested objects.
+func (e Entry) Clone() Entry {
+	e.fields = cloneFields(e.fields)
+	e.tags = append([]string(nil), e.tags...)
+	return e
+}
+
+func cloneFields(fields []Field) []Field {
+	if fields == nil {
+		return nil
+	}
+	out := make([]Field, len(fields))
+	for i, f := range fields {
+		if nested, ok := f.Value.([]Field); ok {
+			f.Value = cloneFields(nested)
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// NewEntry builds an entry, e.g. for testing sinks and hooks or when
+// converting records of other loggers. It gets the next sequence number.
+func NewEntry(t time.Time, level Level, message string, fields ...Field) Entry {
+	return Entry{
+		time:    t,
+		level:   level,
+		message: message,
+		fields:  cloneFields(fields),
+		seq:     entrySeq.Add(1),
+	}
+}
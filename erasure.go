@@ -0,0 +1,264 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// redacted replaces erased messages and identifiers.
+const redacted = "[redacted]"
+
+// Erasure removes the entries of a data subject from the log files of a
+// directory, for right-to-erasure requests. JSON entries mentioning the
+// subject keep their time, level and logger name but lose their message,
+// caller, tags and fields, which become {"redacted":true}. In other lines
+// the subject itself is replaced by "[redacted]". Gzip-compressed files,
+// such as the recordings of a Recorder, are erased the same way. Files are
+// rewritten in place, and the checksums of the Manifest are updated for
+// them. Like Retention, it should only see closed files: a FileSink keeps
+// appending to the replaced copy of a file it has open.
+type Erasure struct {
+	Dir string
+	// Pattern is matched against file names, all files when empty.
+	Pattern string
+	// Manifest is the index of a FileSink writing to Dir (optional).
+	Manifest string
+	// Logger, when set, receives an entry for every rewritten file. The
+	// subject is never logged.
+	Logger *Logger
+	// WORM is the lock of the files, if any. Erasure requests override it:
+	// every locked file rewritten gets a Warn entry on WORM.Logger.
+	WORM *WORM
+}
+
+// ErasedFile is a file rewritten by Erasure.Erase.
+type ErasedFile struct {
+	Path string
+	// Entries is the number of redacted entries or lines.
+	Entries int
+}
+
+type erasureEvent struct {
+	file ErasedFile
+}
+
+func (ev erasureEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("path", ev.file.Path)
+	enc.AddInt("entries", int64(ev.file.Entries))
+}
+
+// Erase redacts the entries matching subject, e.g. a user ID or email
+// address, and returns the files it rewrote.
+func (er *Erasure) Erase(subject string) ([]ErasedFile, error) {
+	if subject == "" {
+		return nil, errors.New("erasure: empty subject")
+	}
+	files, err := scanFiles(er.Dir, er.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("erasure: %w", err)
+	}
+	manifest := filepath.Clean(er.Manifest)
+	var (
+		erased []ErasedFile
+		errs   []error
+	)
+	for _, f := range files {
+		if er.Manifest != "" && filepath.Clean(f.path) == manifest {
+			continue
+		}
+		n, err := eraseFile(f.path, subject)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("erasure: %s: %w", f.path, err))
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		er.WORM.override(f.path, f.modTime, "erasure")
+		ef := ErasedFile{Path: f.path, Entries: n}
+		erased = append(erased, ef)
+		if er.Logger != nil {
+			er.Logger.Info("erased subject from log file", erasureEvent{file: ef})
+		}
+	}
+	if er.Manifest != "" && len(erased) > 0 {
+		if err := rehashManifest(er.Manifest, erased); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return erased, errors.Join(errs...)
+}
+
+// gzipMagic starts gzip-compressed files.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// eraseFile rewrites the file at path without the subject, returning the
+// number of lines changed. Files without a match are left untouched.
+func eraseFile(path, subject string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	compressed := bytes.HasPrefix(data, gzipMagic)
+	if compressed {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return 0, err
+		}
+		if data, err = io.ReadAll(gz); err != nil {
+			return 0, err
+		}
+	}
+
+	escaped, _ := json.Marshal(subject)
+	needles := [][]byte{[]byte(subject), escaped[1 : len(escaped)-1]}
+	var out bytes.Buffer
+	n := 0
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if containsAny(line, needles) {
+				line = eraseLine(line, subject)
+				n++
+			}
+			out.Write(line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if compressed {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(out.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := gz.Close(); err != nil {
+			return 0, err
+		}
+		out = buf
+	}
+	return n, replaceFile(path, out.Bytes(), info.Mode().Perm())
+}
+
+func containsAny(line []byte, needles [][]byte) bool {
+	for _, b := range needles {
+		if bytes.Contains(line, b) {
+			return true
+		}
+	}
+	return false
+}
+
+func eraseLine(line []byte, subject string) []byte {
+	trimmed := bytes.TrimRight(line, "\r\n")
+	eol := line[len(trimmed):]
+	if bytes.HasPrefix(trimmed, defPrefix) {
+		var def templateDef
+		if json.Unmarshal(trimmed, &def) == nil {
+			def.Text = strings.ReplaceAll(def.Text, subject, redacted)
+			if b, err := json.Marshal(def); err == nil {
+				return append(b, eol...)
+			}
+		}
+	}
+	var je jsonEntry
+	if bytes.HasPrefix(trimmed, []byte("{")) && json.Unmarshal(trimmed, &je) == nil && je.Level != "" {
+		je = jsonEntry{
+			Version: je.Version,
+			Time:    je.Time,
+			Seq:     je.Seq,
+			Level:   je.Level,
+			Logger:  je.Logger,
+			Message: redacted,
+			Fields:  json.RawMessage(`{"redacted":true}`),
+		}
+		if b, err := json.Marshal(je); err == nil {
+			return append(b, eol...)
+		}
+	}
+	return bytes.ReplaceAll(line, []byte(subject), []byte(redacted))
+}
+
+// replaceFile atomically replaces the file at path with data.
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// rehashManifest updates the checksums of the erased files in the index.
+func rehashManifest(index string, erased []ErasedFile) error {
+	sums := make(map[string]string)
+	for _, ef := range erased {
+		sum, err := fileSHA256(ef.Path)
+		if err != nil {
+			return fmt.Errorf("manifest: %w", err)
+		}
+		sums[filepath.Clean(ef.Path)] = sum
+	}
+	b, err := os.ReadFile(index)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	info, err := os.Stat(index)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	var out bytes.Buffer
+	for i, line := range bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n")) {
+		var m ManifestEntry
+		if len(line) > 0 {
+			if err := json.Unmarshal(line, &m); err != nil {
+				return fmt.Errorf("manifest: line %d: %w", i+1, err)
+			}
+			if sum, ok := sums[filepath.Clean(m.File)]; ok {
+				m.SHA256 = sum
+				if line, err = json.Marshal(m); err != nil {
+					return err
+				}
+			}
+		}
+		out.Write(append(line, '\n'))
+	}
+	if err := replaceFile(index, out.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrorErr logs err at Error level with the caller, followed by the chain
+// of errors it wraps, innermost last:
+//
+//	l.ErrorErr(err, "loading config")
+//	// ↳ loading config error="read app.yaml: open app.yaml: no such file"
+//	//   chain.0="read app.yaml" chain.1="open app.yaml" chain.2="no such file"
+//
+// Without msg the error itself is the message. A stack trace carried by err
+// is added as a stack field, and the errors of errors.Join are listed one by
+// one, see Errors.
+func (l *Logger) ErrorErr(err error, msg ...interface{}) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	e := makeErrorEntry(err, msg, l.caller(LevelError))
+	e.args = len(msg) > 0
+	l.log(e)
+}
+
+func makeErrorEntry(err error, msg []interface{}, caller *callerRef) Entry {
+	message, fields := splitArgs(msg)
+	if prefix, errs := splitMultiError(err); errs != nil {
+		var ev errorEvent
+		if message == "" {
+			message = prefix
+		} else {
+			ev.err = prefix
+		}
+		if message == "" {
+			message = strconv.Itoa(len(errs)) + " errors"
+		}
+		fields = append(append(marshalFields(ev), marshalFields(Errors(errs))...), fields...)
+		return makeEntry(LevelError, message, fields, caller)
+	}
+	ev := errorEvent{chain: errorChain(err), stack: errorStack(err)}
+	if err != nil && message != "" {
+		ev.err = err.Error()
+	} else if err != nil {
+		message = err.Error()
+	}
+	return makeEntry(LevelError, message, append(marshalFields(ev), fields...), caller)
+}
+
+// Errors attaches a list of errors, such as the failures of a batch, as an
+// errors object with one field per error, numbered from 0. Errors wrapping
+// others are objects with their chain, and with their stack when they
+// carry one:
+//
+//	l.Error("import failed", logger.Errors(errs))
+//	// ↳ import failed errors.0="row 3: bad date" errors.1.error=... errors.1.chain.0=...
+//
+// ErrorErr lists the errors of errors.Join the same way.
+type Errors []error
+
+func (errs Errors) MarshalLog(enc FieldEncoder) {
+	enc.AddObject("errors", errorList(errs))
+}
+
+type errorList []error
+
+func (errs errorList) MarshalLog(enc FieldEncoder) {
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		ev := errorEvent{err: err.Error(), chain: errorChain(err), stack: errorStack(err)}
+		if len(ev.chain) <= 1 && ev.stack == "" {
+			enc.AddString(strconv.Itoa(i), ev.err)
+			continue
+		}
+		enc.AddObject(strconv.Itoa(i), ev)
+	}
+}
+
+// splitMultiError finds the errors.Join-style error of more than one error
+// in the chain of err. It returns the text the errors wrapping it add and
+// its errors, or nil when there is none.
+func splitMultiError(err error) (string, []error) {
+	var prefix []string
+	for err != nil {
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			errs := u.Unwrap()
+			if len(errs) < 2 {
+				return "", nil
+			}
+			return strings.Join(prefix, ": "), errs
+		case interface{ Unwrap() error }:
+			inner := u.Unwrap()
+			if inner == nil {
+				return "", nil
+			}
+			prefix = append(prefix, strings.TrimSuffix(err.Error(), ": "+inner.Error()))
+			err = inner
+		default:
+			return "", nil
+		}
+	}
+	return "", nil
+}
+
+type errorEvent struct {
+	err   string
+	chain []string
+	stack stackTrace
+}
+
+func (ev errorEvent) MarshalLog(enc FieldEncoder) {
+	if ev.err != "" {
+		enc.AddString("error", ev.err)
+	}
+	if len(ev.chain) > 1 {
+		enc.AddObject("chain", errorChainFields(ev.chain))
+	}
+	if ev.stack != "" {
+		enc.AddAny("stack", ev.stack)
+	}
+}
+
+type errorChainFields []string
+
+func (c errorChainFields) MarshalLog(enc FieldEncoder) {
+	for i, s := range c {
+		enc.AddString(strconv.Itoa(i), s)
+	}
+}
+
+// errorChain splits err into the text each wrapped error adds, outermost
+// first. The errors of errors.Join are walked in order.
+func errorChain(err error) []string {
+	var out []string
+	var walk func(err error)
+	walk = func(err error) {
+		switch u := err.(type) {
+		case nil:
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			inner := u.Unwrap()
+			if inner == nil {
+				out = append(out, err.Error())
+				return
+			}
+			out = append(out, strings.TrimSuffix(err.Error(), ": "+inner.Error()))
+			walk(inner)
+		default:
+			out = append(out, err.Error())
+		}
+	}
+	walk(err)
+	return out
+}
+
+// errorStack returns the stack trace carried by err: the innermost one
+// exposed as program counters by a Callers method, as by go-errors, or the
+// frames printed by the %+v verb, as by pkg/errors.
+func errorStack(err error) stackTrace {
+	var pcs []uintptr
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if c, ok := e.(interface{ Callers() []uintptr }); ok {
+			pcs = c.Callers()
+		}
+	}
+	if len(pcs) > 0 {
+		return callersStack(pcs)
+	}
+	if _, ok := err.(fmt.Formatter); !ok {
+		return ""
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	if verbose == err.Error() || !strings.Contains(verbose, "\n") {
+		return ""
+	}
+	return stackTrace(strings.Trim(strings.TrimPrefix(verbose, err.Error()), "\n"))
+}
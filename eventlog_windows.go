@@ -0,0 +1,169 @@
+//go:build windows
+
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+	procRegCreateKeyExW       = advapi32.NewProc("RegCreateKeyExW")
+	procRegSetValueExW        = advapi32.NewProc("RegSetValueExW")
+)
+
+const (
+	eventlogErrorType   = 0x0001
+	eventlogWarningType = 0x0002
+
+	eventSourceKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+)
+
+// Event IDs written by EventLog, one per level so they can be filtered in
+// the Event Viewer.
+const (
+	EventIDWarn  = 1
+	EventIDError = 2
+	EventIDAlert = 3
+	EventIDFatal = 4
+	EventIDPanic = 5
+)
+
+// EventLog is a Sink writing Warn and higher entries to the Windows Event
+// Log under a registered source; lower levels are ignored.
+type EventLog struct {
+	handle syscall.Handle
+}
+
+// InstallEventSource registers source in the Application log, with
+// EventCreate.exe as the message file so entries are shown verbatim. It needs
+// administrator rights and succeeds when the source already exists.
+func InstallEventSource(source string) error {
+	path, err := syscall.UTF16PtrFromString(eventSourceKey + source)
+	if err != nil {
+		return err
+	}
+	var key syscall.Handle
+	var disposition uint32
+	r, _, _ := procRegCreateKeyExW.Call(
+		uintptr(syscall.HKEY_LOCAL_MACHINE),
+		uintptr(unsafe.Pointer(path)),
+		0, 0, 0,
+		syscall.KEY_WRITE,
+		0,
+		uintptr(unsafe.Pointer(&key)),
+		uintptr(unsafe.Pointer(&disposition)),
+	)
+	if r != 0 {
+		return fmt.Errorf("event log: install %q: %w", source, syscall.Errno(r))
+	}
+	defer syscall.RegCloseKey(key)
+
+	file, err := syscall.UTF16FromString(`%SystemRoot%\System32\EventCreate.exe`)
+	if err != nil {
+		return err
+	}
+	if err := regSetValue(key, "EventMessageFile", syscall.REG_EXPAND_SZ,
+		unsafe.Pointer(&file[0]), uint32(len(file)*2)); err != nil {
+		return fmt.Errorf("event log: install %q: %w", source, err)
+	}
+	types := uint32(eventlogErrorType | eventlogWarningType | 0x0004)
+	if err := regSetValue(key, "TypesSupported", syscall.REG_DWORD,
+		unsafe.Pointer(&types), 4); err != nil {
+		return fmt.Errorf("event log: install %q: %w", source, err)
+	}
+	return nil
+}
+
+func regSetValue(key syscall.Handle, name string, typ uint32, data unsafe.Pointer, size uint32) error {
+	p, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return err
+	}
+	r, _, _ := procRegSetValueExW.Call(
+		uintptr(key),
+		uintptr(unsafe.Pointer(p)),
+		0,
+		uintptr(typ),
+		uintptr(data),
+		uintptr(size),
+	)
+	if r != 0 {
+		return syscall.Errno(r)
+	}
+	return nil
+}
+
+// NewEventLog opens the event source, see InstallEventSource.
+func NewEventLog(source string) (*EventLog, error) {
+	p, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(p)))
+	if h == 0 {
+		return nil, fmt.Errorf("event log: open %q: %w", source, err)
+	}
+	return &EventLog{handle: syscall.Handle(h)}, nil
+}
+
+func (s *EventLog) WriteEntry(e Entry) error {
+	var typ, id uint32
+	switch {
+	case e.level >= LevelFatal:
+		typ, id = eventlogErrorType, EventIDFatal
+	case e.level >= LevelPanic:
+		typ, id = eventlogErrorType, EventIDPanic
+	case e.level >= LevelAlert:
+		typ, id = eventlogErrorType, EventIDAlert
+	case e.level >= LevelError:
+		typ, id = eventlogErrorType, EventIDError
+	case e.level >= LevelWarn:
+		typ, id = eventlogWarningType, EventIDWarn
+	default:
+		return nil
+	}
+
+	msg := joinFields(e.message, e.fields, nil)
+	caller := e.Caller()
+	if !caller.IsZero() {
+		msg = caller.Function + ":" + strconv.Itoa(caller.Line) + " " + msg
+	}
+	if e.name != "" {
+		msg = "[" + e.name + "] " + msg
+	}
+	p, err := syscall.UTF16PtrFromString(msg)
+	if err != nil {
+		return err
+	}
+	strs := []*uint16{p}
+	r, _, err := procReportEventW.Call(
+		uintptr(s.handle),
+		uintptr(typ),
+		0,
+		uintptr(id),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&strs[0])),
+		0,
+	)
+	if r == 0 {
+		return fmt.Errorf("event log: %w", err)
+	}
+	return nil
+}
+
+func (s *EventLog) Close() error {
+	r, _, err := procDeregisterEventSource.Call(uintptr(s.handle))
+	if r == 0 {
+		return fmt.Errorf("event log: %w", err)
+	}
+	return nil
+}
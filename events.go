@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Events counts the entries logged with Logger.Event, for basic usage
+// analytics without an external service.
+type Events struct {
+	// Interval, when set, logs an "event counts" Info entry with the number
+	// of every event since the previous one, until Stop or Logger.Close.
+	Interval time.Duration
+	// Sink, when set, also receives every event entry, e.g. to forward them
+	// to an analytics service.
+	Sink Sink
+
+	once   sync.Once
+	stop   chan struct{}
+	halt   sync.Once
+	mu     sync.Mutex
+	total  map[string]int64
+	recent map[string]int64
+}
+
+// Counts returns the number of every event since the logger was created.
+func (ev *Events) Counts() map[string]int64 {
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	out := make(map[string]int64, len(ev.total))
+	for k, v := range ev.total {
+		out[k] = v
+	}
+	return out
+}
+
+func (ev *Events) count(name string) {
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	if ev.total == nil {
+		ev.total = make(map[string]int64)
+		ev.recent = make(map[string]int64)
+	}
+	ev.total[name]++
+	ev.recent[name]++
+}
+
+// start reports the counts every Interval through l, once for all the
+// loggers sharing ev.
+func (ev *Events) start(l *Logger) {
+	if ev.Interval <= 0 {
+		return
+	}
+	ev.once.Do(func() {
+		ev.stop = make(chan struct{})
+		ticker := time.NewTicker(ev.Interval)
+		go func() {
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ev.stop:
+					return
+				case <-ticker.C:
+				}
+				ev.mu.Lock()
+				counts := eventCounts(ev.recent)
+				ev.recent = make(map[string]int64)
+				ev.mu.Unlock()
+				if len(counts) > 0 && l.enabled(LevelInfo) {
+					l.log(makeEntry(LevelInfo, "event counts", marshalFields(counts), nil))
+				}
+			}
+		}()
+	})
+}
+
+// Stop ends the periodic "event counts" entries. Events are still counted.
+func (ev *Events) Stop() {
+	ev.once.Do(func() {}) // a later start does nothing
+	ev.halt.Do(func() {
+		if ev.stop != nil {
+			close(ev.stop)
+		}
+	})
+}
+
+type eventCounts map[string]int64
+
+func (c eventCounts) MarshalLog(enc FieldEncoder) {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		enc.AddInt(name, c[name])
+	}
+}
+
+// Event logs a usage event, e.g. l.Event("export_clicked", fields), as an
+// Info entry with the name as message and an event field. With
+// Config.Events it is counted and forwarded to Events.Sink.
+func (l *Logger) Event(name string, fields ...LogMarshaler) {
+	if l.nop {
+		return
+	}
+	f := []Field{{Key: "event", Value: name}}
+	for _, m := range fields {
+		f = append(f, marshalFields(m)...)
+	}
+	e := makeEntry(LevelInfo, name, f, nil)
+	if ev := l.c.Events; ev != nil {
+		ev.count(name)
+		if ev.Sink != nil {
+			l.writeSinks([]Sink{ev.Sink}, l.withContext(e))
+		}
+	}
+	if l.enabled(LevelInfo) {
+		l.log(e)
+	}
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Every returns a logger that writes an entry at most once per interval
+// for the same level and message. Repeats within the interval are
+// suppressed and counted, and when it ends the entry is logged once more
+// with a repeated field holding their number:
+//
+//	for err := connect(); err != nil; err = connect() {
+//		l.Every(time.Second).Warn("retrying connect") // ... retrying connect repeated=4213 window=1s
+//	}
+//
+// Loggers returned for the same interval share their counts, so Every can
+// be called in the loop itself.
+func (l *Logger) Every(interval time.Duration) *Logger {
+	if interval <= 0 || l.limits == nil {
+		return l
+	}
+	child := *l
+	child.every = l.limits.get(interval)
+	return &child
+}
+
+// limiters holds the limiter of every interval used with Every.
+type limiters struct {
+	mu sync.Mutex
+	m  map[time.Duration]*limiter
+}
+
+func (ls *limiters) get(d time.Duration) *limiter {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.m == nil {
+		ls.m = make(map[time.Duration]*limiter)
+	}
+	lim := ls.m[d]
+	if lim == nil {
+		lim = &limiter{interval: d, windows: make(map[limitKey]*limitWindow)}
+		ls.m[d] = lim
+	}
+	return lim
+}
+
+type limitKey struct {
+	level   Level
+	name    string
+	message string
+}
+
+type limitWindow struct {
+	first      Entry
+	suppressed int
+}
+
+type limiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	windows  map[limitKey]*limitWindow
+}
+
+// allow reports whether e opens a new window. The summary of the window is
+// logged through l when it ends.
+func (lim *limiter) allow(l *Logger, e Entry) bool {
+	key := limitKey{level: e.level, name: e.name, message: e.message}
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	if w, ok := lim.windows[key]; ok {
+		w.suppressed++
+		return false
+	}
+	lim.windows[key] = &limitWindow{first: e}
+	time.AfterFunc(lim.interval, func() { lim.end(l, key) })
+	return true
+}
+
+func (lim *limiter) end(l *Logger, key limitKey) {
+	lim.mu.Lock()
+	w := lim.windows[key]
+	delete(lim.windows, key)
+	lim.mu.Unlock()
+	if w == nil || w.suppressed == 0 {
+		return
+	}
+	fields := append(w.first.fields[:len(w.first.fields):len(w.first.fields)],
+		Field{Key: "repeated", Value: w.suppressed},
+		Field{Key: "window", Value: lim.interval},
+	)
+	e := makeEntry(w.first.level, w.first.message, fields, w.first.caller)
+	e.name, e.tags = w.first.name, w.first.tags
+	l.output(e)
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// SetEntryMigration replaces the migration from schema v for the duration
+// of the test.
+func SetEntryMigration(t testing.TB, v int, migrate func(raw map[string]json.RawMessage) error) {
+	prev, ok := entryMigrations[v]
+	entryMigrations[v] = migrate
+	t.Cleanup(func() {
+		if ok {
+			entryMigrations[v] = prev
+		} else {
+			delete(entryMigrations, v)
+		}
+	})
+}
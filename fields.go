@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is a structured key/value pair attached to an entry. Nested objects
+// are stored as a []Field value.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalLog makes a Field usable as a log argument:
+//
+//	l.Info("user logged in", logger.Field{Key: "user_id", Value: 7})
+func (f Field) MarshalLog(enc FieldEncoder) {
+	if m, ok := f.Value.(LogMarshaler); ok {
+		enc.AddObject(f.Key, m)
+		return
+	}
+	enc.AddAny(f.Key, f.Value)
+}
+
+// Fields attaches every key of the map as a field, in sorted order:
+//
+//	l.Info("user logged in", logger.Fields{"user_id": 7, "tenant": "acme"})
+type Fields map[string]interface{}
+
+func (fs Fields) MarshalLog(enc FieldEncoder) {
+	for _, f := range fs.sorted() {
+		f.MarshalLog(enc)
+	}
+}
+
+func (fs Fields) sorted() []Field {
+	keys := make([]string, 0, len(fs))
+	for k := range fs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]Field, len(keys))
+	for i, k := range keys {
+		fields[i] = Field{Key: k, Value: fs[k]}
+	}
+	return fields
+}
+
+// LogMarshaler is implemented by types that describe themselves as fields.
+// Values passed to a log call that implement it are encoded as fields of the
+// entry instead of being printed into the message.
+type LogMarshaler interface {
+	MarshalLog(enc FieldEncoder)
+}
+
+type FieldEncoder interface {
+	AddString(key, value string)
+	AddInt(key string, value int64)
+	AddFloat(key string, value float64)
+	AddBool(key string, value bool)
+	AddTime(key string, value time.Time)
+	AddDuration(key string, value time.Duration)
+	AddObject(key string, value LogMarshaler)
+	AddAny(key string, value interface{})
+}
+
+type fieldEncoder struct {
+	fields []Field
+}
+
+func (enc *fieldEncoder) add(key string, value interface{}) {
+	enc.fields = append(enc.fields, Field{Key: key, Value: value})
+}
+
+func (enc *fieldEncoder) AddString(key, value string)                 { enc.add(key, value) }
+func (enc *fieldEncoder) AddInt(key string, value int64)              { enc.add(key, value) }
+func (enc *fieldEncoder) AddFloat(key string, value float64)          { enc.add(key, value) }
+func (enc *fieldEncoder) AddBool(key string, value bool)              { enc.add(key, value) }
+func (enc *fieldEncoder) AddTime(key string, value time.Time)         { enc.add(key, value) }
+func (enc *fieldEncoder) AddDuration(key string, value time.Duration) { enc.add(key, value) }
+func (enc *fieldEncoder) AddAny(key string, value interface{})        { enc.add(key, value) }
+
+func (enc *fieldEncoder) AddObject(key string, value LogMarshaler) {
+	enc.add(key, marshalFields(value))
+}
+
+func marshalFields(m LogMarshaler) []Field {
+	enc := &fieldEncoder{}
+	m.MarshalLog(enc)
+	return enc.fields
+}
+
+// splitArgs builds the message from args, encoding LogMarshaler values as
+// fields instead.
+func splitArgs(args []interface{}) (string, []Field) {
+	n := 0
+	for _, arg := range args {
+		if _, ok := arg.(LogMarshaler); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return fmt.Sprint(args...), nil
+	}
+	enc := &fieldEncoder{}
+	rest := make([]interface{}, 0, len(args)-n)
+	for _, arg := range args {
+		if m, ok := arg.(LogMarshaler); ok {
+			m.MarshalLog(enc)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return fmt.Sprint(rest...), enc.fields
+}
+
+func formatFieldValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			return strconv.Quote(v)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case error:
+		return strconv.Quote(v.Error())
+	}
+	return fmt.Sprint(v)
+}
+
+// appendFields renders fields as key=value pairs, flattening nested objects
+// into dotted keys.
+func appendFields(b *strings.Builder, prefix string, fields []Field, style func(string) string) {
+	for _, f := range fields {
+		key := f.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := f.Value.([]Field); ok {
+			appendFields(b, key, nested, style)
+			continue
+		}
+		switch f.Value.(type) {
+		case stackTrace, dumpValue:
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(style(key + "="))
+		b.WriteString(formatFieldValue(f.Value))
+	}
+}
+
+func marshalFieldsJSON(fields []Field) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+
+		var value []byte
+		switch v := f.Value.(type) {
+		case []Field:
+			value, err = marshalFieldsJSON(v)
+		case time.Duration:
+			value, err = json.Marshal(v.String())
+		case error:
+			value, err = json.Marshal(v.Error())
+		default:
+			value, err = json.Marshal(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Key, err)
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// unmarshalFieldsJSON decodes a JSON object into fields, keeping the order
+// of keys and representing nested objects as []Field.
+func unmarshalFieldsJSON(data []byte) ([]Field, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("fields: expected an object")
+	}
+	return decodeFieldsObject(dec)
+}
+
+func decodeFieldsObject(dec *json.Decoder) ([]Field, error) {
+	var fields []Field
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("fields: expected a key")
+		}
+		value, err := decodeFieldValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func decodeFieldValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			return decodeFieldsObject(dec)
+		}
+		var list []interface{}
+		for dec.More() {
+			v, err := decodeFieldValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return list, nil
+	case json.Number:
+		if i, err := tok.Int64(); err == nil {
+			return i, nil
+		}
+		return tok.Float64()
+	}
+	return tok, nil
+}
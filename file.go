@@ -0,0 +1,336 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FileOptions struct {
+	Format Format
+	// Vars are constant placeholders of the path template, e.g. "service".
+	Vars map[string]string
+	// Retention, when set, is enforced in the background after every
+	// midnight rollover, once the first file of the new day is open. Files
+	// the sink has open are never deleted, and errors are logged to
+	// Retention.Logger.
+	Retention *Retention
+	// Manifest, when set, is the index file a ManifestEntry is appended to
+	// for every file closed by the sink.
+	Manifest string
+	// Archiver, when set, uploads every file closed by a rollover. Files
+	// open when the sink is closed are left for the rollover of the next
+	// sink appending to them.
+	Archiver *Archiver
+	// Dictionary stores repeated messages of FormatJSON files once per file
+	// as a template referenced by the entries. DecodeStream resolves them.
+	Dictionary bool
+	// WORM, when set, locks the files for a retention period. It is also
+	// applied to Retention and Archiver unless they have their own.
+	WORM *WORM
+}
+
+// FileSink writes entries to files whose path is built from a template such
+// as "logs/{service}/{date}/app-{level}.log". Besides Vars, the template can
+// use {date} (2006-01-02 of the entry time), {level} and {name} (the logger
+// name). Missing directories are created, and files of the previous day are
+// closed once the first entry after midnight is written. Entries dated
+// earlier than the current day go to its files.
+type FileSink struct {
+	mu    sync.Mutex
+	parts []string // literal text at even indexes, placeholders at odd ones
+	opts  FileOptions
+	f     *formatter
+	day   string
+	files map[string]*sinkFile
+}
+
+type sinkFile struct {
+	*os.File
+	first, last time.Time
+	count       int
+	dict        dictEncoder
+}
+
+func NewFileSink(template string, opts FileOptions) (*FileSink, error) {
+	parts, err := parsePathTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(parts); i += 2 {
+		switch parts[i] {
+		case "date", "level", "name":
+		default:
+			if _, ok := opts.Vars[parts[i]]; !ok {
+				return nil, fmt.Errorf("file sink: unknown placeholder {%s}", parts[i])
+			}
+		}
+	}
+	if opts.WORM != nil {
+		if opts.Retention != nil && opts.Retention.WORM == nil {
+			opts.Retention.WORM = opts.WORM
+		}
+		if opts.Archiver != nil && opts.Archiver.WORM == nil {
+			opts.Archiver.WORM = opts.WORM
+		}
+	}
+	return &FileSink{
+		parts: parts,
+		opts:  opts,
+		f:     &formatter{theme: &Theme{}},
+		files: make(map[string]*sinkFile),
+	}, nil
+}
+
+func parsePathTemplate(s string) ([]string, error) {
+	var parts []string
+	for {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			if strings.IndexByte(s, '}') >= 0 {
+				return nil, fmt.Errorf("file sink: unexpected '}' in template")
+			}
+			return append(parts, s), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("file sink: unclosed '{' in template")
+		}
+		name := s[start+1 : start+end]
+		if name == "" {
+			return nil, fmt.Errorf("file sink: empty placeholder in template")
+		}
+		parts = append(parts, s[:start], name)
+		s = s[start+end+1:]
+	}
+}
+
+// pathSafe keeps values substituted into the template from adding
+// directories of their own.
+var pathSafe = strings.NewReplacer("/", "_", `\`, "_", "..", "_")
+
+func (s *FileSink) path(e Entry, day string) string {
+	var b strings.Builder
+	for i, p := range s.parts {
+		if i%2 == 0 {
+			b.WriteString(p)
+			continue
+		}
+		var v string
+		switch p {
+		case "date":
+			v = day
+		case "level":
+			v = e.level.String()
+		case "name":
+			v = e.name
+		default:
+			v = s.opts.Vars[p]
+		}
+		b.WriteString(pathSafe.Replace(v))
+	}
+	return filepath.Clean(b.String())
+}
+
+func (s *FileSink) WriteEntry(e Entry) error {
+	line, err := s.line(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.file(e)
+	if err != nil {
+		return err
+	}
+	if line, err = s.dictLine(f, e, line); err != nil {
+		return err
+	}
+	_, err = f.Write(line)
+	return err
+}
+
+// WriteEntries writes entries with one write per file, as the Async mode of
+// a Logger does.
+func (s *FileSink) WriteEntries(entries []Entry) error {
+	lines := make([][]byte, len(entries))
+	for i, e := range entries {
+		line, err := s.line(e)
+		if err != nil {
+			return err
+		}
+		lines[i] = line
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		order []*sinkFile
+		bufs  = make(map[*sinkFile][]byte)
+	)
+	flush := func() error {
+		for _, f := range order {
+			if _, err := f.Write(bufs[f]); err != nil {
+				return err
+			}
+		}
+		order, bufs = order[:0], make(map[*sinkFile][]byte)
+		return nil
+	}
+	for i, e := range entries {
+		if e.time.Format("2006-01-02") > s.day {
+			// The files are closed on rollover.
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		f, err := s.file(e)
+		if err != nil {
+			return err
+		}
+		line, err := s.dictLine(f, e, lines[i])
+		if err != nil {
+			return err
+		}
+		if _, ok := bufs[f]; !ok {
+			order = append(order, f)
+		}
+		bufs[f] = append(bufs[f], line...)
+	}
+	return flush()
+}
+
+// line formats e, leaving dictionary lines to dictLine.
+func (s *FileSink) line(e Entry) ([]byte, error) {
+	switch {
+	case s.opts.Format == FormatJSON && s.opts.Dictionary:
+		return nil, nil
+	case s.opts.Format == FormatJSON:
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	return []byte(s.f.plain(e) + "\n"), nil
+}
+
+func (s *FileSink) dictLine(f *sinkFile, e Entry, line []byte) ([]byte, error) {
+	if s.opts.Format != FormatJSON || !s.opts.Dictionary {
+		return line, nil
+	}
+	// Templates are defined per file, so the line depends on it.
+	return f.dict.appendEntry(nil, e)
+}
+
+// file returns the file of e, rolling over to a new day first, and counts
+// the entry. An entry dated before the current day, logged late or after
+// the clock was stepped back, goes to the files of the current day, as
+// those of its own day may be closed and sealed. s.mu must be held.
+func (s *FileSink) file(e Entry) (*sinkFile, error) {
+	day := e.time.Format("2006-01-02")
+	rolled := false
+	if day > s.day {
+		if err := s.closeFiles(true); err != nil {
+			return nil, err
+		}
+		rolled = s.day != ""
+		s.day = day
+	}
+	f, err := s.open(s.path(e, s.day))
+	if err != nil {
+		return nil, err
+	}
+	if rolled && s.opts.Retention != nil {
+		// Started once the new file is open, so it is skipped.
+		go s.opts.Retention.enforceLogged(s.isOpen)
+	}
+	if f.count == 0 || e.time.Before(f.first) {
+		f.first = e.time
+	}
+	if e.time.After(f.last) {
+		f.last = e.time
+	}
+	f.count++
+	return f, nil
+}
+
+// isOpen reports whether the sink has the file at path open.
+func (s *FileSink) isOpen(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range s.files {
+		if q, err := filepath.Abs(p); err == nil && q == abs {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *FileSink) open(path string) (*sinkFile, error) {
+	if f, ok := s.files[path]; ok {
+		return f, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("file sink: %w", err)
+	}
+	// Files are never truncated: entries of a restarted process are appended.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("file sink: %w", err)
+	}
+	sf := &sinkFile{File: f}
+	s.files[path] = sf
+	return sf, nil
+}
+
+// closeFiles closes the open files. On rollover the files are complete, so
+// a WORM sink seals them.
+func (s *FileSink) closeFiles(rollover bool) error {
+	var first error
+	for path, f := range s.files {
+		err := f.Close()
+		if err == nil && rollover {
+			err = s.opts.WORM.seal(path)
+		}
+		if err == nil && s.opts.Manifest != "" && f.count > 0 {
+			err = appendManifest(s.opts.Manifest, path, f.first, f.last, f.count)
+		}
+		if err == nil && rollover && s.opts.Archiver != nil {
+			// A file closed with the sink may still be appended to by the
+			// next process, and uploading it now would overwrite its key.
+			s.opts.Archiver.Archive(path)
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+		delete(s.files, path)
+	}
+	return first
+}
+
+// Flush commits the open files to disk.
+func (s *FileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var first error
+	for _, f := range s.files {
+		if err := f.Sync(); err != nil && first == nil {
+			first = fmt.Errorf("file sink: %w", err)
+		}
+	}
+	return first
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeFiles(false)
+}
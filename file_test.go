@@ -0,0 +1,607 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pecet3/logger"
+)
+
+func decodeEntry(t *testing.T, s string) logger.Entry {
+	t.Helper()
+	var e logger.Entry
+	if err := json.Unmarshal([]byte(s), &e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{service}/{date}/app-{level}.log"), logger.FileOptions{
+		Vars: map[string]string{"service": "api"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	for _, s := range []string{
+		`{"v":1,"time":"2024-03-01T23:59:58Z","level":"info","msg":"one"}`,
+		`{"v":1,"time":"2024-03-01T23:59:59Z","level":"error","msg":"two"}`,
+		`{"v":1,"time":"2024-03-02T00:00:01Z","level":"info","msg":"three"}`,
+	} {
+		if err := sink.WriteEntry(decodeEntry(t, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for path, want := range map[string]string{
+		"api/2024-03-01/app-info.log":  "one",
+		"api/2024-03-01/app-error.log": "two",
+		"api/2024-03-02/app-info.log":  "three",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 1 || !strings.HasSuffix(lines[0], want) {
+			t.Errorf("%s: got %q, want a single %q entry", path, b, want)
+		}
+	}
+
+	if _, err := logger.NewFileSink("logs/{host}.log", logger.FileOptions{}); err == nil {
+		t.Error("expected an error for an unknown placeholder")
+	}
+}
+
+func TestRetention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"a/old.log", "b/mid.log", "b/new.log"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mod := now.Add(-time.Duration(3-i) * time.Hour)
+		os.Chtimes(path, mod, mod)
+	}
+
+	var out bytes.Buffer
+	r := &logger.Retention{
+		Dir:      dir,
+		MaxBytes: 150,
+		DryRun:   true,
+		Logger:   logger.New(&logger.Config{Output: &out, Theme: &logger.Theme{}}),
+	}
+	deleted, err := r.Enforce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || !strings.Contains(out.String(), "would delete") {
+		t.Fatalf("dry run: deleted %v, logged %q", deleted, out.String())
+	}
+	if _, err := os.Stat(deleted[0]); err != nil {
+		t.Fatal("dry run removed a file")
+	}
+
+	r.DryRun = false
+	r.MaxBytes = 0
+	r.MaxAge = 150 * time.Minute
+	if deleted, _ = r.Enforce(); len(deleted) != 1 || !strings.HasSuffix(deleted[0], "old.log") {
+		t.Fatalf("max age: deleted %v", deleted)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Error("empty directory should have been removed")
+	}
+}
+
+func TestFileSink_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "manifest.ndjson")
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{date}.log"), logger.FileOptions{Manifest: index})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`{"v":1,"time":"2024-03-01T10:00:00Z","level":"info","msg":"one"}`,
+		`{"v":1,"time":"2024-03-01T12:00:00Z","level":"info","msg":"two"}`,
+		`{"v":1,"time":"2024-03-02T08:00:00Z","level":"info","msg":"three"}`,
+	} {
+		if err := sink.WriteEntry(decodeEntry(t, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := logger.ReadManifest(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Entries != 2 || entries[1].Entries != 1 {
+		t.Fatalf("unexpected manifest: %+v", entries)
+	}
+	day := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	if !entries[0].Overlaps(day, day) || entries[1].Overlaps(day, day) {
+		t.Error("wrong time range lookup")
+	}
+	if err := entries[0].Verify(); err != nil {
+		t.Error(err)
+	}
+	os.WriteFile(entries[0].File, []byte("tampered\n"), 0644)
+	if err := entries[0].Verify(); err == nil {
+		t.Error("expected a checksum mismatch")
+	}
+}
+
+type memStore struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (s *memStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = string(b)
+	return nil
+}
+
+func TestLogger_Async(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := logger.NewFileSink(path, logger.FileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{sink}, Async: &logger.Async{BufferSize: 16}})
+	for i := 0; i < 50; i++ {
+		l.Info("entry ", i)
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 50 || !strings.HasSuffix(lines[49], "entry 49") {
+		t.Fatalf("Sync should wait for every queued entry, got %d lines", len(lines))
+	}
+	l.Close()
+	l.Info("after close")
+	if b, _ = os.ReadFile(path); !strings.Contains(string(b), "after close") {
+		t.Error("entries logged after Close should be written synchronously")
+	}
+}
+
+func TestErasure(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "manifest.ndjson")
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{date}.log"), logger.FileOptions{Format: logger.FormatJSON, Manifest: manifest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{sink}})
+	l.Info("order placed", logger.Fields{"email": "ada@example.com"})
+	l.Info("cache warmed")
+	l.Warn("bounce from ada@example.com")
+	sink.Close()
+
+	er := &logger.Erasure{Dir: dir, Manifest: manifest}
+	erased, err := er.Erase("ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(erased) != 1 || erased[0].Entries != 2 {
+		t.Fatalf("got %+v, want one file with two entries", erased)
+	}
+	f, _ := os.Open(erased[0].Path)
+	defer f.Close()
+	entries, _ := logger.DecodeStream(f)
+	var got []string
+	for e := range entries {
+		got = append(got, e.Level().String()+":"+e.Message())
+	}
+	if strings.Join(got, ",") != "info:[redacted],info:cache warmed,warn:[redacted]" {
+		t.Errorf("got entries %v", got)
+	}
+	b, _ := os.ReadFile(erased[0].Path)
+	if strings.Contains(string(b), "ada@") {
+		t.Errorf("the subject is still in the file:\n%s", b)
+	}
+
+	records, err := logger.ReadManifest(manifest)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("manifest: %v %v", records, err)
+	}
+	if err := records[0].Verify(); err != nil {
+		t.Errorf("the checksum should match the rewritten file: %v", err)
+	}
+}
+
+func TestErasure_RecordingAndWORM(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.rec")
+	rec, err := logger.NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.WriteEntry(logger.NewEntry(time.Now(), logger.LevelInfo, "login ada@example.com"))
+	rec.WriteEntry(logger.NewEntry(time.Now(), logger.LevelInfo, "idle"))
+	rec.Close()
+	os.Chmod(path, 0444)
+
+	var out bytes.Buffer
+	worm := &logger.WORM{Period: time.Hour, Logger: logger.New(&logger.Config{Output: &out})}
+	er := &logger.Erasure{Dir: dir, WORM: worm}
+	if erased, err := er.Erase("ada@example.com"); err != nil || len(erased) != 1 || erased[0].Entries != 1 {
+		t.Fatalf("got %+v, %v", erased, err)
+	}
+	f, _ := os.Open(path)
+	defer f.Close()
+	rr, err := logger.NewRecordingReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := rr.Next(); e.Message() != "[redacted]" {
+		t.Errorf("got %q, want the recorded entry redacted", e.Message())
+	}
+	if !strings.Contains(out.String(), "overrode the lock of a log file") {
+		t.Errorf("the WORM override should be logged: %s", out.String())
+	}
+}
+
+func TestFileSink_WORM(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	worm := &logger.WORM{Period: time.Hour, Logger: logger.New(&logger.Config{Output: &out})}
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{date}.log"), logger.FileOptions{WORM: worm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)
+	logger.SetClock(logger.ClockFunc(func() time.Time { return day }))
+	t.Cleanup(func() { logger.SetClock(nil) })
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{sink}})
+	l.Info("first day")
+	day = day.Add(2 * time.Minute)
+	l.Info("second day")
+	sink.Close()
+
+	first := filepath.Join(dir, "2025-03-01.log")
+	if info, err := os.Stat(first); err != nil || info.Mode().Perm()&0222 != 0 {
+		t.Fatalf("the file of the previous day should be read-only: %v %v", info.Mode(), err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(filepath.Join(dir, "2025-03-02.log"), time.Now().Add(-30*time.Minute), time.Now().Add(-30*time.Minute))
+	os.Chtimes(first, old, old)
+
+	retention := &logger.Retention{Dir: dir, MaxAge: time.Minute, WORM: worm}
+	deleted, _ := retention.Enforce()
+	if len(deleted) != 1 || deleted[0] != first {
+		t.Fatalf("only the file past its lock should be deleted, got %v", deleted)
+	}
+	if !strings.Contains(out.String(), "refused to delete locked log file") {
+		t.Errorf("the refused deletion should be logged: %s", out.String())
+	}
+}
+
+func TestFileSink_RetentionSkipsOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.log")
+	os.WriteFile(old, []byte("old\n"), 0644)
+	os.Chtimes(old, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	retention := &logger.Retention{Dir: dir, MaxBytes: 1}
+	sink, err := logger.NewFileSink(filepath.Join(dir, "app.log"), logger.FileOptions{Retention: retention})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`{"v":1,"time":"2024-03-01T10:00:00Z","level":"info","msg":"one"}`,
+		`{"v":1,"time":"2024-03-02T08:00:00Z","level":"info","msg":"two"}`,
+	} {
+		if err := sink.WriteEntry(decodeEntry(t, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(old); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	sink.Close()
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("the rotated file should have been deleted")
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "app.log")); err != nil || !strings.Contains(string(data), "two") {
+		t.Errorf("the open file should be kept: %q %v", data, err)
+	}
+}
+
+func TestFileSink_LateEntries(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "manifest.ndjson")
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{date}.log"), logger.FileOptions{Manifest: manifest, WORM: &logger.WORM{Period: time.Hour}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`{"v":1,"time":"2026-01-02T10:00:00Z","level":"info","msg":"one"}`,
+		`{"v":1,"time":"2026-01-01T23:59:00Z","level":"info","msg":"late"}`,
+		`{"v":1,"time":"2026-01-02T10:01:00Z","level":"info","msg":"two"}`,
+	} {
+		if err := sink.WriteEntry(decodeEntry(t, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sink.Close()
+
+	b, _ := os.ReadFile(filepath.Join(dir, "2026-01-02.log"))
+	if strings.Count(string(b), "\n") != 3 {
+		t.Errorf("the late entry should go to the current file:\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-01-01.log")); !os.IsNotExist(err) {
+		t.Error("no file should be opened for the late entry")
+	}
+	if records, err := logger.ReadManifest(manifest); err != nil || len(records) != 1 {
+		t.Errorf("got %d manifest records, want 1: %v", len(records), err)
+	}
+}
+
+func TestFileSink_Archiver(t *testing.T) {
+	dir := t.TempDir()
+	store := &memStore{objects: make(map[string]string)}
+	archiver := &logger.Archiver{Store: store, Prefix: "api", Root: dir, DeleteAfterUpload: true}
+	sink, err := logger.NewFileSink(filepath.Join(dir, "{date}/{level}.log"), logger.FileOptions{Archiver: archiver})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`{"v":1,"time":"2024-03-01T10:00:00Z","level":"info","msg":"one"}`,
+		`{"v":1,"time":"2024-03-02T08:00:00Z","level":"info","msg":"two"}`,
+	} {
+		if err := sink.WriteEntry(decodeEntry(t, s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archiver.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(store.objects["api/2024-03-01/info.log"], "one\n") || len(store.objects) != 1 {
+		t.Fatalf("unexpected objects: %v", store.objects)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024-03-01/info.log")); !os.IsNotExist(err) {
+		t.Error("uploaded file should have been deleted")
+	}
+	sink.Close()
+	archiver.Close()
+	if len(store.objects) != 1 {
+		t.Errorf("the file of the current day should not be archived on Close: %v", store.objects)
+	}
+}
+
+func TestFileSink_Dictionary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := logger.NewFileSink(path, logger.FileOptions{Format: logger.FormatJSON, Dictionary: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for i := 0; i < 3; i++ {
+		for _, msg := range []string{
+			fmt.Sprintf("user %d logged in after %dms", i, i*10),
+			"cache  warmed\tup",
+			fmt.Sprintf("job-%d done", i),
+		} {
+			want = append(want, msg)
+			e := decodeEntry(t, fmt.Sprintf(`{"v":1,"time":"2024-03-01T10:00:00Z","level":"info","msg":%q}`, msg))
+			if err := sink.WriteEntry(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), `"def":`); n != 3 {
+		t.Errorf("got %d template definitions, want 3:\n%s", n, b)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		// Readers of v1 must reject the lines rather than decode empty
+		// messages.
+		var v struct {
+			Version int `json:"v"`
+		}
+		if err := json.Unmarshal([]byte(line), &v); err != nil || v.Version != 2 {
+			t.Errorf("dictionary line should have schema v2: %s", line)
+		}
+	}
+	entries, errc := logger.DecodeStream(bytes.NewReader(b))
+	var got []string
+	for e := range entries {
+		got = append(got, e.Message())
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got messages %q, want %q", got, want)
+	}
+}
+
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	l := logger.New(&logger.Config{Output: f, Format: logger.FormatJSON})
+	l.Info("already written")
+
+	var out lockedBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- logger.Follow(path, &out, logger.FollowOptions{Context: ctx, Poll: 5 * time.Millisecond})
+	}()
+	waitFor := func(substr string) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); !strings.Contains(out.String(), substr); {
+			if time.Now().After(deadline) {
+				t.Fatalf("%q not rendered:\n%s", substr, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	l.Warn("disk almost full")
+	f.WriteString("plain text line\n")
+	waitFor("plain text line")
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rotated.Close()
+	l.SetOutput(rotated)
+	l.Error("after rotation")
+	waitFor("after rotation")
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "already written") || !strings.Contains(got, "[ WARN ]") || !strings.Contains(got, "disk almost full") {
+		t.Errorf("only new entries should be rendered with the console formatter:\n%s", got)
+	}
+}
+
+func TestFollow_Created(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var out lockedBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go logger.Follow(path, &out, logger.FollowOptions{Context: ctx, Poll: 5 * time.Millisecond})
+
+	time.Sleep(20 * time.Millisecond)
+	os.WriteFile(path, []byte(`{"v":1,"time":"2024-03-01T10:00:00Z","level":"info","msg":"first entry"}`+"\n"), 0644)
+	for deadline := time.Now().Add(2 * time.Second); !strings.Contains(out.String(), "first entry"); {
+		if time.Now().After(deadline) {
+			t.Fatalf("a file created after Follow started should be rendered from its start:\n%s", out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	if dir := os.Getenv("LOGGER_FATAL_DIR"); dir != "" {
+		sink, err := logger.NewFileSink(filepath.Join(dir, "app.log"), logger.FileOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{sink}})
+		l.Fatalf("database %s unreachable", "orders")
+		return
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLogger_Fatal$")
+	cmd.Env = append(os.Environ(), "LOGGER_FATAL_DIR="+dir)
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Fatalf("got %v, want exit status 1", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "FATAL") || !strings.Contains(string(b), "database orders unreachable") {
+		t.Errorf("the entry was not written before exiting: %q", b)
+	}
+}
+
+func TestAuditLogger(t *testing.T) {
+	if _, err := logger.NewAudit(&logger.AuditConfig{}); err == nil {
+		t.Error("an audit logger without sinks should be refused")
+	}
+
+	dir := t.TempDir()
+	sink, err := logger.NewFileSink(filepath.Join(dir, "audit.log"), logger.FileOptions{Format: logger.FormatJSON})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	audit, err := logger.NewAudit(&logger.AuditConfig{Sinks: []logger.Sink{sink}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Log("alice", "user.delete", "user:42", logger.Fields{"reason": "gdpr"}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := decodeEntry(t, strings.TrimSpace(string(b)))
+	var keys []string
+	for _, f := range e.Fields() {
+		keys = append(keys, fmt.Sprintf("%s=%v", f.Key, f.Value))
+	}
+	if e.Name() != "audit" || strings.Join(keys, " ") != "actor=alice action=user.delete target=user:42 reason=gdpr" {
+		t.Errorf("unexpected audit entry: %s %v", e.Name(), keys)
+	}
+
+	failing, _ := logger.NewAudit(&logger.AuditConfig{Sinks: []logger.Sink{sink, failingSink{}}})
+	if err := failing.Log("bob", "login", "app"); err == nil || !strings.Contains(err.Error(), "audit sink 1: disk full") {
+		t.Errorf("sink errors should be returned: %v", err)
+	}
+}
@@ -0,0 +1,504 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is a compiled filter expression, for example
+//
+//	level >= warn && msg contains "timeout" && fields.tenant == "acme"
+//
+// Operands are level, msg, logger, caller, tags and fields.<key> (nested
+// fields use dotted keys); tags only supports contains, which tests for a
+// tag. Operators are == != < <= > >= contains startswith
+// endswith and matches (regular expression), combined with && || ! and
+// parentheses. A bare fields.<key> is true when the field is set and is not
+// false, zero or empty.
+type Filter struct {
+	expr  string
+	match func(Entry) bool
+}
+
+func CompileFilter(expr string) (*Filter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("filter %q: %w", expr, err)
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("filter %q: unexpected %q", expr, p.peek().text)
+	}
+	return &Filter{expr: expr, match: match}, nil
+}
+
+func MustCompileFilter(expr string) *Filter {
+	f, err := CompileFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Match reports whether e satisfies the filter. A nil Filter matches
+// everything.
+func (f *Filter) Match(e Entry) bool {
+	if f == nil {
+		return true
+	}
+	return f.match(e)
+}
+
+func (f *Filter) String() string {
+	return f.expr
+}
+
+type filterSink struct {
+	sink   Sink
+	filter *Filter
+}
+
+// FilterSink passes to s only the entries matching f.
+func FilterSink(s Sink, f *Filter) Sink {
+	return &filterSink{sink: s, filter: f}
+}
+
+func (s *filterSink) WriteEntry(e Entry) error {
+	if !s.filter.Match(e) {
+		return nil
+	}
+	return s.sink.WriteEntry(e)
+}
+
+func (s *filterSink) Flush() error {
+	if f, ok := s.sink.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+}
+
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("filter %q: unterminated string", s)
+			}
+			text, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("filter %q: %w", s, err)
+			}
+			tokens = append(tokens, filterToken{tokString, text})
+			i = j + 1
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"),
+			strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="),
+			strings.HasPrefix(s[i:], "<="), strings.HasPrefix(s[i:], ">="):
+			tokens = append(tokens, filterToken{tokOp, s[i : i+2]})
+			i += 2
+		case strings.ContainsRune("()<>!", rune(c)):
+			tokens = append(tokens, filterToken{tokOp, string(c)})
+			i++
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && (s[j] == '.' || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, filterToken{tokNumber, s[i:j]})
+			i = j
+		case c == '_' || isIdentStart(s[i:]):
+			_, size := utf8.DecodeRuneInString(s[i:])
+			j := i + size
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, filterToken{tokIdent, s[i:j]})
+			i = j
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return nil, fmt.Errorf("filter %q: unexpected character %q", s, r)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	if p.done() {
+		return filterToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	if p.done() {
+		return filterToken{}, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) accept(op string) bool {
+	if t := p.peek(); !p.done() && t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (func(Entry) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e Entry) bool { return l(e) || right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (func(Entry) bool, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e Entry) bool { return l(e) && right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (func(Entry) bool, error) {
+	if p.accept("!") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(e Entry) bool { return !inner(e) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+var filterOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"contains": true, "startswith": true, "endswith": true, "matches": true,
+}
+
+func (p *filterParser) parseComparison() (func(Entry) bool, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind != tokIdent {
+		return nil, fmt.Errorf("expected an operand, got %q", t.text)
+	}
+	operand := t.text
+
+	op := p.peek()
+	if p.done() || !filterOperators[op.text] {
+		if !strings.HasPrefix(operand, "fields.") {
+			return nil, fmt.Errorf("expected an operator after %q", operand)
+		}
+		path := strings.Split(strings.TrimPrefix(operand, "fields."), ".")
+		return func(e Entry) bool {
+			v, ok := lookupField(e.fields, path)
+			return ok && truthy(v)
+		}, nil
+	}
+	p.pos++
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if value.kind == tokOp {
+		return nil, fmt.Errorf("expected a value after %q, got %q", op.text, value.text)
+	}
+
+	switch {
+	case operand == "level":
+		return compileLevelComparison(op.text, value)
+	case operand == "msg" || operand == "message":
+		return compileStringComparison(op.text, value, Entry.Message)
+	case operand == "logger" || operand == "name":
+		return compileStringComparison(op.text, value, Entry.Name)
+	case operand == "caller":
+		return compileStringComparison(op.text, value, func(e Entry) string { return e.Caller().Function })
+	case operand == "tags":
+		if op.text != "contains" || value.kind != tokString {
+			return nil, fmt.Errorf("tags only supports contains with a string")
+		}
+		return func(e Entry) bool { return e.hasTag(value.text) }, nil
+	case strings.HasPrefix(operand, "fields."):
+		return compileFieldComparison(op.text, value, strings.Split(strings.TrimPrefix(operand, "fields."), "."))
+	}
+	return nil, fmt.Errorf("unknown operand %q", operand)
+}
+
+func compileLevelComparison(op string, value filterToken) (func(Entry) bool, error) {
+	lv, err := ParseLevel(value.text)
+	if err != nil {
+		return nil, err
+	}
+	cmp, err := compareInts(op)
+	if err != nil {
+		return nil, err
+	}
+	return func(e Entry) bool { return cmp(int(e.level), int(lv)) }, nil
+}
+
+func compareInts(op string) (func(a, b int) bool, error) {
+	switch op {
+	case "==":
+		return func(a, b int) bool { return a == b }, nil
+	case "!=":
+		return func(a, b int) bool { return a != b }, nil
+	case "<":
+		return func(a, b int) bool { return a < b }, nil
+	case "<=":
+		return func(a, b int) bool { return a <= b }, nil
+	case ">":
+		return func(a, b int) bool { return a > b }, nil
+	case ">=":
+		return func(a, b int) bool { return a >= b }, nil
+	}
+	return nil, fmt.Errorf("operator %q is not supported here", op)
+}
+
+func compileStringComparison(op string, value filterToken, get func(Entry) string) (func(Entry) bool, error) {
+	match, err := stringMatcher(op, value.text)
+	if err != nil {
+		return nil, err
+	}
+	return func(e Entry) bool { return match(get(e)) }, nil
+}
+
+func stringMatcher(op, want string) (func(string) bool, error) {
+	switch op {
+	case "==":
+		return func(s string) bool { return s == want }, nil
+	case "!=":
+		return func(s string) bool { return s != want }, nil
+	case "contains":
+		return func(s string) bool { return strings.Contains(s, want) }, nil
+	case "startswith":
+		return func(s string) bool { return strings.HasPrefix(s, want) }, nil
+	case "endswith":
+		return func(s string) bool { return strings.HasSuffix(s, want) }, nil
+	case "matches":
+		re, err := regexp.Compile(want)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	case "<":
+		return func(s string) bool { return s < want }, nil
+	case "<=":
+		return func(s string) bool { return s <= want }, nil
+	case ">":
+		return func(s string) bool { return s > want }, nil
+	case ">=":
+		return func(s string) bool { return s >= want }, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func compileFieldComparison(op string, value filterToken, path []string) (func(Entry) bool, error) {
+	if value.kind == tokNumber {
+		want, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := compareFloats(op)
+		if err != nil {
+			return nil, err
+		}
+		return func(e Entry) bool {
+			v, ok := lookupField(e.fields, path)
+			if !ok {
+				return false
+			}
+			f, ok := toFloat(v)
+			return ok && cmp(f, want)
+		}, nil
+	}
+	if value.kind == tokIdent && (value.text == "true" || value.text == "false") && (op == "==" || op == "!=") {
+		want := value.text == "true"
+		return func(e Entry) bool {
+			v, ok := lookupField(e.fields, path)
+			b, isBool := v.(bool)
+			return (ok && isBool && b == want) == (op == "==")
+		}, nil
+	}
+	match, err := stringMatcher(op, value.text)
+	if err != nil {
+		return nil, err
+	}
+	return func(e Entry) bool {
+		v, ok := lookupField(e.fields, path)
+		if !ok {
+			return op == "!="
+		}
+		s, isString := v.(string)
+		if !isString {
+			s = fmt.Sprint(v)
+		}
+		return match(s)
+	}, nil
+}
+
+func compareFloats(op string) (func(a, b float64) bool, error) {
+	switch op {
+	case "==":
+		return func(a, b float64) bool { return a == b }, nil
+	case "!=":
+		return func(a, b float64) bool { return a != b }, nil
+	case "<":
+		return func(a, b float64) bool { return a < b }, nil
+	case "<=":
+		return func(a, b float64) bool { return a <= b }, nil
+	case ">":
+		return func(a, b float64) bool { return a > b }, nil
+	case ">=":
+		return func(a, b float64) bool { return a >= b }, nil
+	}
+	return nil, fmt.Errorf("operator %q needs a string value", op)
+}
+
+func lookupField(fields []Field, path []string) (interface{}, bool) {
+	for i, key := range path {
+		found := false
+		for _, f := range fields {
+			if f.Key != key {
+				continue
+			}
+			if i == len(path)-1 {
+				return f.Value, true
+			}
+			nested, ok := f.Value.([]Field)
+			if !ok {
+				return nil, false
+			}
+			fields = nested
+			found = true
+			break
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
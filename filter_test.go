@@ -0,0 +1,152 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+type tenant struct {
+	name  string
+	tries int
+	audit bool
+}
+
+func (t tenant) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddString("tenant", t.name)
+	enc.AddInt("tries", int64(t.tries))
+	enc.AddBool("audit", t.audit)
+}
+
+func recordEntries(t *testing.T) *logger.Ring {
+	t.Helper()
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: &bytes.Buffer{}, Sinks: []logger.Sink{ring}})
+	l.Info("request ok", tenant{name: "acme", tries: 1})
+	l.Warn("upstream timeout", tenant{name: "acme", tries: 3})
+	l.Warn("upstream timeout", tenant{name: "globex", tries: 2, audit: true})
+	l.Error("database unreachable")
+	return ring
+}
+
+func TestCompileFilter(t *testing.T) {
+	ring := recordEntries(t)
+	tests := []struct {
+		expr string
+		want int
+	}{
+		{`level >= warn`, 3},
+		{`level >= warn && msg contains "timeout" && fields.tenant == "acme"`, 1},
+		{`fields.tries > 1`, 2},
+		{`fields.audit`, 1},
+		{`fields.audit == false`, 2},
+		{`!(level == info) && msg startswith "upstream"`, 2},
+		{`level == error || fields.tenant != "acme"`, 2},
+		{`msg matches "^data.*able$"`, 1},
+	}
+	for _, tt := range tests {
+		entries, err := logger.Query(ring, tt.expr).Entries()
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if len(entries) != tt.want {
+			t.Errorf("%s: matched %d entries, want %d", tt.expr, len(entries), tt.want)
+		}
+	}
+
+	for _, expr := range []string{`level >=`, `level >= loud`, `msg "x"`, `(level == info`, `msg contains "x`, `msg == &&`, `msg == (`} {
+		if _, err := logger.CompileFilter(expr); err == nil {
+			t.Errorf("%s: expected a compile error", expr)
+		}
+	}
+
+	ring = logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: &bytes.Buffer{}, Sinks: []logger.Sink{ring}})
+	l.Info("signed in", logger.Fields{"użytkownik": "a"})
+	l.Info("signed in", logger.Fields{"użytkownik": "b"})
+	entries, err := logger.Query(ring, `fields.użytkownik == "a"`).Entries()
+	if err != nil || len(entries) != 1 {
+		t.Errorf("non-ASCII key: matched %d entries, err %v", len(entries), err)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	var out bytes.Buffer
+	audit := logger.NewRing(10)
+	alerts := logger.NewRing(10)
+	l := logger.New(&logger.Config{
+		Output: &out,
+		Routes: []logger.Route{
+			{Filter: logger.MustCompileFilter(`fields.audit == true`), Sinks: []logger.Sink{audit}, Exclusive: true},
+			{Filter: logger.MustCompileFilter(`level >= error`), Sinks: []logger.Sink{alerts}},
+		},
+	})
+	l.Info("user deleted", tenant{name: "acme", audit: true})
+	l.Error("payment failed")
+	l.Info("request ok")
+
+	if audit.Len() != 1 || bytes.Contains(out.Bytes(), []byte("user deleted")) {
+		t.Errorf("audit entries should only reach the audit sink")
+	}
+	if alerts.Len() != 1 || !bytes.Contains(out.Bytes(), []byte("payment failed")) {
+		t.Errorf("errors should be duplicated to the alert sink")
+	}
+}
+
+func TestQueryResult_WriteCSV(t *testing.T) {
+	ring := recordEntries(t)
+	var b bytes.Buffer
+	if err := logger.Query(ring, `level == warn`).WriteCSV(&b, "tenant", "tries", "missing"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and 2 rows", len(records))
+	}
+	if got := strings.Join(records[0], ","); got != "time,level,logger,message,caller,tenant,tries,missing" {
+		t.Errorf("header = %s", got)
+	}
+	if got := strings.Join(records[2][1:], ","); got != "warn,,upstream timeout,,globex,2," {
+		t.Errorf("row = %s", got)
+	}
+
+	b.Reset()
+	logger.Query(ring, `level == error`).WriteTSV(&b)
+	if lines := strings.Split(strings.TrimSpace(b.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "\terror\t") {
+		t.Errorf("unexpected TSV: %q", b.String())
+	}
+	if err := logger.Query(ring, `level >=`).WriteCSV(&b); err == nil {
+		t.Error("expected the filter error")
+	}
+}
+
+func TestLogger_Tag(t *testing.T) {
+	ring := logger.NewRing(10)
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, Theme: &logger.Theme{}, Sinks: []logger.Sink{ring}})
+	db := l.Tag("db")
+	db.Tag("retry").Warn("deadlock, retrying")
+	db.Info("connected")
+	l.Info("untagged")
+
+	entries, err := logger.Query(ring, `tags contains "retry"`).Entries()
+	if err != nil || len(entries) != 1 || strings.Join(entries[0].Tags(), ",") != "db,retry" {
+		t.Fatalf("unexpected tagged entries: %v %v", entries, err)
+	}
+	if n, _ := logger.Query(ring, `tags contains "db"`).Entries(); len(n) != 2 {
+		t.Errorf("got %d db entries, want 2", len(n))
+	}
+	if !strings.Contains(out.String(), "#db #retry") {
+		t.Errorf("tags missing from the console output: %q", out.String())
+	}
+	if _, err := logger.CompileFilter(`tags == "db"`); err == nil {
+		t.Error("expected an error for tags ==")
+	}
+}
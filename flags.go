@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"flag"
+	"os"
+)
+
+// Flags holds the logger settings chosen on the command line. It is filled
+// by BindFlags or by the cobra/urfave-cli adapters.
+type Flags struct {
+	Verbosity int
+	Quiet     bool
+	Format    string
+	File      string
+}
+
+// BindFlags registers -v, -vv, --quiet, --log-format and --log-file on fs
+// (flag.CommandLine when nil). Call Apply after parsing to configure a logger.
+func BindFlags(fs *flag.FlagSet) *Flags {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	f := &Flags{Format: "console"}
+	fs.BoolFunc("v", "verbose output, show info messages", func(string) error {
+		f.Verbosity = max(f.Verbosity, 1)
+		return nil
+	})
+	fs.BoolFunc("vv", "very verbose output, show debug messages", func(string) error {
+		f.Verbosity = max(f.Verbosity, 2)
+		return nil
+	})
+	fs.BoolVar(&f.Quiet, "quiet", false, "only show errors")
+	fs.StringVar(&f.Format, "log-format", f.Format, "log output format: console or json")
+	fs.StringVar(&f.File, "log-file", "", "append logs to the given file instead of stdout")
+	return f
+}
+
+// Level is the minimum level selected by the flags: Warn by default, Info
+// with -v, Debug with -vv and Error with --quiet.
+func (f *Flags) Level() Level {
+	switch {
+	case f.Quiet:
+		return LevelError
+	case f.Verbosity >= 2:
+		return LevelDebug
+	case f.Verbosity == 1:
+		return LevelInfo
+	}
+	return LevelWarn
+}
+
+func (f *Flags) Apply(c *Config) error {
+	format, err := ParseFormat(f.Format)
+	if err != nil {
+		return err
+	}
+	c.Format = format
+	c.Level = f.Level()
+	if c.AtomicLevel != nil {
+		c.AtomicLevel.SetLevel(c.Level)
+	}
+	if f.File != "" {
+		file, err := os.OpenFile(f.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		c.Output = file
+	}
+	return nil
+}
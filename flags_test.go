@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+func TestBindFlags(t *testing.T) {
+	tests := []struct {
+		args  []string
+		level logger.Level
+	}{
+		{nil, logger.LevelWarn},
+		{[]string{"-v"}, logger.LevelInfo},
+		{[]string{"-vv"}, logger.LevelDebug},
+		{[]string{"--quiet", "-vv"}, logger.LevelError},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := logger.BindFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		c := &logger.Config{}
+		if err := flags.Apply(c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Level != tt.level {
+			t.Errorf("%v: got level %v, want %v", tt.args, c.Level, tt.level)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := logger.BindFlags(fs)
+	if err := fs.Parse([]string{"--log-format", "xml"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := flags.Apply(&logger.Config{}); err == nil {
+		t.Error("expected an error for an unknown log format")
+	}
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+type FollowOptions struct {
+	// Context stops Follow when it is done.
+	Context context.Context
+	// FromStart renders the entries already in the file first; otherwise
+	// only those written after Follow started are, and of the lines before
+	// only the message templates of a Dictionary file are read. A file
+	// created after Follow started is rendered from its start.
+	FromStart bool
+	// Filter, when set, renders only the matching entries.
+	Filter *Filter
+	// Poll is how often the file is checked for new lines, 250ms by default.
+	Poll time.Duration
+	// Config styles the output as the console of a logger with the same
+	// Color, Theme, Template and other console settings would.
+	Config *Config
+}
+
+// Follow tails the NDJSON log file at path, written by another process
+// with FormatJSON or a FileSink, and renders its entries to w with the
+// console formatter, like tail -f with colors. A file rotated or truncated
+// under it is followed from its start. Lines that are not entries are
+// copied as they are. Follow returns when the context is done or reading
+// fails:
+//
+//	err := logger.Follow("/var/log/app/app.log", os.Stdout, logger.FollowOptions{Context: ctx})
+func Follow(path string, w io.Writer, opts FollowOptions) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	poll := opts.Poll
+	if poll <= 0 {
+		poll = 250 * time.Millisecond
+	}
+	c := opts.Config
+	if c == nil {
+		c = &Config{}
+	}
+	f := newFormatter(c, w)
+	fl := &follower{path: path, renderFrom: -1}
+	if _, err := os.Stat(path); opts.FromStart || errors.Is(err, fs.ErrNotExist) {
+		fl.renderFrom = 0
+	}
+	render := func(line []byte, e Entry, ok bool) {
+		switch {
+		case !ok:
+			writeLine(w, string(line))
+		case opts.Filter.Match(e):
+			writeLine(w, f.render(e))
+		}
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	defer fl.close()
+	for {
+		if err := fl.poll(render); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type follower struct {
+	path string
+	file *os.File
+	info os.FileInfo
+	// offset is where the next line of file starts, renderFrom the offset
+	// of the first line rendered; -1 until the file is first opened.
+	offset     int64
+	renderFrom int64
+	pending    []byte
+	dict       dictDecoder
+}
+
+// poll renders the lines added to the file since the last call, switching
+// to a new file at path once the current one is read to its end.
+func (fl *follower) poll(render func(line []byte, e Entry, ok bool)) error {
+	if fl.file != nil {
+		if err := fl.read(render); err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(fl.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Between the rename and the creation of a rotated file.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fl.file != nil && os.SameFile(info, fl.info) && info.Size() >= fl.offset {
+		return nil
+	}
+	fl.close()
+	if fl.file, err = os.Open(fl.path); err != nil {
+		return err
+	}
+	if fl.renderFrom < 0 {
+		fl.renderFrom = info.Size()
+	} else {
+		fl.renderFrom = 0
+	}
+	fl.info, fl.offset, fl.pending, fl.dict = info, 0, nil, dictDecoder{}
+	return fl.read(render)
+}
+
+const (
+	// followChunk is how much of the file is read at once.
+	followChunk = 64 << 10
+	// maxFollowLine bounds the unfinished line kept between reads; a longer
+	// one is handled as it is.
+	maxFollowLine = 1 << 20
+)
+
+func (fl *follower) read(render func(line []byte, e Entry, ok bool)) error {
+	buf := make([]byte, followChunk)
+	for {
+		n, err := fl.file.Read(buf)
+		if n > 0 {
+			fl.lines(buf[:n], render)
+		}
+		if errors.Is(err, io.EOF) || n == 0 && err == nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// lines handles the complete lines of data following the pending one.
+func (fl *follower) lines(data []byte, render func(line []byte, e Entry, ok bool)) {
+	data = append(fl.pending, data...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		start := fl.offset
+		fl.offset += int64(i + 1)
+		fl.line(bytes.TrimSpace(data[:i]), start, render)
+		data = data[i+1:]
+	}
+	if len(data) > maxFollowLine {
+		start := fl.offset
+		fl.offset += int64(len(data))
+		fl.line(data, start, render)
+		data = nil
+	}
+	fl.pending = append([]byte(nil), data...)
+}
+
+func (fl *follower) line(line []byte, start int64, render func(line []byte, e Entry, ok bool)) {
+	if len(line) == 0 {
+		return
+	}
+	if start < fl.renderFrom {
+		// Not rendered, but the entries after it may use its template.
+		if bytes.HasPrefix(line, defPrefix) {
+			fl.dict.decode(line)
+		}
+		return
+	}
+	e, ok, err := fl.dict.decode(line)
+	if err == nil && !ok {
+		// A message template, remembered by the decoder.
+		return
+	}
+	render(line, e, err == nil)
+}
+
+func (fl *follower) close() {
+	if fl.file != nil {
+		fl.file.Close()
+		fl.file = nil
+	}
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	runtimedebug "runtime/debug"
+	"time"
+)
+
+type GoOption func(*goOptions)
+
+type goOptions struct {
+	restart     bool
+	maxRestarts int
+	backoff     time.Duration
+}
+
+// minGoBackoff and maxGoBackoff bound the delay between restarts, so a
+// function panicking right away is not restarted in a busy loop.
+const (
+	minGoBackoff = 100 * time.Millisecond
+	maxGoBackoff = time.Minute
+)
+
+// Restart runs the function again after it panicked, up to max times (no
+// limit when max is zero), waiting backoff (at least 100ms) before the
+// first restart and twice as long before every next one, up to a minute.
+func Restart(max int, backoff time.Duration) GoOption {
+	return func(o *goOptions) {
+		o.restart = true
+		o.maxRestarts = max
+		o.backoff = backoff
+	}
+}
+
+// Go runs fn in a new goroutine, recovering from panics. A panic is logged
+// as an Error entry with the panic value and stack trace as fields and the
+// caller of Go as caller, so it ends up as a single structured entry instead
+// of crashing the process.
+func (l *Logger) Go(fn func(), opts ...GoOption) {
+	var o goOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	caller := l.caller(LevelError)
+	go func() {
+		backoff := min(max(o.backoff, minGoBackoff), maxGoBackoff)
+		for restarts := 0; ; restarts++ {
+			ev, panicked := l.run(fn)
+			if !panicked {
+				return
+			}
+			ev.restarts = restarts
+			last := !o.restart || (o.maxRestarts > 0 && restarts >= o.maxRestarts)
+			ev.restarting = !last
+			if l.enabled(LevelError) {
+				l.log(makeEntry(LevelError, "goroutine panicked", marshalFields(ev), caller))
+			}
+			if last {
+				return
+			}
+			time.Sleep(backoff)
+			backoff = min(backoff*2, maxGoBackoff)
+		}
+	}()
+}
+
+func (l *Logger) run(fn func()) (ev panicEvent, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ev = panicEvent{value: r, stack: string(runtimedebug.Stack())}
+			panicked = true
+		}
+	}()
+	fn()
+	return panicEvent{}, false
+}
+
+type panicEvent struct {
+	value      interface{}
+	stack      string
+	restarts   int
+	restarting bool
+}
+
+func (ev panicEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("panic", panicString(ev.value))
+	enc.AddString("stack", ev.stack)
+	if ev.restarts > 0 {
+		enc.AddInt("restarts", int64(ev.restarts))
+	}
+	if ev.restarting {
+		enc.AddBool("restarting", true)
+	}
+}
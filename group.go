@@ -0,0 +1,42 @@
+package logger
+
+// Group returns a logger for a multi-step operation. Its entries, and those
+// of the loggers derived from it, carry a group field with a new ID, and a
+// parent field with the group of l when l belongs to one, so the steps of an
+// operation can be put back together from the logs alone:
+//
+//	op := l.Group()
+//	op.Info("checkout started")         // group=3f2a...
+//	op.Group().Info("charging card")    // group=9b1c... parent=3f2a...
+//
+// Select the entries of a group with a filter such as
+// fields.group == "3f2a..." or fields.parent == "3f2a...".
+func (l *Logger) Group() *Logger {
+	child := *l
+	child.link = []Field{{Key: "group", Value: newRunID()}}
+	if len(l.link) > 0 {
+		child.link = append(child.link, Field{Key: "parent", Value: l.link[0].Value})
+	}
+	return &child
+}
+
+// Group is the group ID of the entry, set by Logger.Group.
+func (e Entry) Group() string {
+	return e.stringField("group")
+}
+
+// Parent is the group ID of the operation that started the group of the
+// entry, see Logger.Group.
+func (e Entry) Parent() string {
+	return e.stringField("parent")
+}
+
+func (e Entry) stringField(key string) string {
+	for _, f := range e.fields {
+		if f.Key == key {
+			s, _ := f.Value.(string)
+			return s
+		}
+	}
+	return ""
+}
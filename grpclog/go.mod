@@ -0,0 +1,16 @@
+module github.com/pecet3/logger/grpclog
+
+go 1.23.4
+
+require (
+	github.com/pecet3/logger v0.1.0
+	google.golang.org/grpc v1.67.3
+)
+
+require (
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
@@ -0,0 +1,149 @@
+// Package grpclog provides gRPC server interceptors writing a log entry for
+// every call.
+package grpclog
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pecet3/logger"
+)
+
+// Call describes a handled call. It is attached to the log entry as fields.
+type Call struct {
+	Method   string // full method name, e.g. "/orders.v1.Orders/Get"
+	Stream   bool
+	Peer     string
+	Code     codes.Code
+	Error    string
+	Duration time.Duration
+	// Metadata holds the values of Options.Metadata sent by the client.
+	Metadata map[string]string
+}
+
+func (c Call) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddString("method", c.Method)
+	if c.Stream {
+		enc.AddBool("stream", true)
+	}
+	if c.Peer != "" {
+		enc.AddString("peer", c.Peer)
+	}
+	enc.AddString("code", c.Code.String())
+	if c.Error != "" {
+		enc.AddString("error", c.Error)
+	}
+	enc.AddDuration("duration", c.Duration)
+	for _, key := range slices.Sorted(maps.Keys(c.Metadata)) {
+		enc.AddString(strings.ReplaceAll(key, "-", "_"), c.Metadata[key])
+	}
+}
+
+type Options struct {
+	// Metadata lists the incoming metadata keys attached as fields, with
+	// dashes turned into underscores. By default x-request-id.
+	Metadata []string
+	// Level chooses the level of a call from its status code, CodeLevel by
+	// default.
+	Level func(codes.Code) logger.Level
+	// Skip, when set, drops the entries of the methods it returns true for,
+	// e.g. health checks.
+	Skip func(method string) bool
+}
+
+// CodeLevel logs client mistakes and expected outcomes at Info, codes
+// that may need attention at Warn and server failures at Error.
+func CodeLevel(code codes.Code) logger.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return logger.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return logger.LevelWarn
+	}
+	return logger.LevelError
+}
+
+// UnaryServerInterceptor logs every unary call with its method, peer,
+// status code and duration.
+func UnaryServerInterceptor(l *logger.Logger, opts Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		opts.log(l, ctx, info.FullMethod, false, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs every streaming call when it ends, like
+// UnaryServerInterceptor.
+func StreamServerInterceptor(l *logger.Logger, opts Options) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		opts.log(l, ss.Context(), info.FullMethod, true, start, err)
+		return err
+	}
+}
+
+func (o *Options) log(l *logger.Logger, ctx context.Context, method string, stream bool, start time.Time, err error) {
+	if o.Skip != nil && o.Skip(method) {
+		return
+	}
+	c := Call{
+		Method:   method,
+		Stream:   stream,
+		Code:     status.Code(err),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		c.Error = status.Convert(err).Message()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		c.Peer = p.Addr.String()
+	}
+	keys := o.Metadata
+	if keys == nil {
+		keys = []string{"x-request-id"}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range keys {
+		if v := md.Get(key); len(v) > 0 {
+			if c.Metadata == nil {
+				c.Metadata = make(map[string]string, len(keys))
+			}
+			c.Metadata[strings.ToLower(key)] = v[0]
+		}
+	}
+
+	level := CodeLevel
+	if o.Level != nil {
+		level = o.Level
+	}
+	logAt(l, level(c.Code), method+" "+c.Code.String(), c)
+}
+
+func logAt(l *logger.Logger, lv logger.Level, msg string, c Call) {
+	switch {
+	case lv >= logger.LevelAlert:
+		l.Alert(msg, c)
+	case lv >= logger.LevelError:
+		l.Error(msg, c)
+	case lv >= logger.LevelWarn:
+		l.Warn(msg, c)
+	case lv >= logger.LevelInfo:
+		l.Info(msg, c)
+	default:
+		l.Debug(msg, c)
+	}
+}
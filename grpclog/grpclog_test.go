@@ -0,0 +1,88 @@
+package grpclog_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pecet3/logger"
+	"github.com/pecet3/logger/grpclog"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context { return s.ctx }
+
+func fields(e logger.Entry) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, f := range e.Fields() {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	intercept := grpclog.UnaryServerInterceptor(l, grpclog.Options{})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 4711}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", "req-42"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Get"}
+	intercept(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	intercept(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "database down")
+	})
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	e := entries[0]
+	f := fields(e)
+	if e.Level() != logger.LevelInfo || e.Message() != "/orders.v1.Orders/Get OK" {
+		t.Errorf("unexpected entry: %s %q", e.Level(), e.Message())
+	}
+	if f["peer"] != "10.0.0.7:4711" || f["code"] != "OK" || f["x_request_id"] != "req-42" {
+		t.Errorf("unexpected fields: %v", f)
+	}
+	if e := entries[1]; e.Level() != logger.LevelError || fields(e)["error"] != "database down" {
+		t.Errorf("an unavailable call should be an error: %s %v", e.Level(), e.Fields())
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	intercept := grpclog.StreamServerInterceptor(l, grpclog.Options{
+		Skip: func(method string) bool { return method == "/grpc.health.v1.Health/Watch" },
+	})
+
+	ss := serverStream{ctx: context.Background()}
+	intercept(nil, ss, &grpc.StreamServerInfo{FullMethod: "/orders.v1.Orders/Watch"}, func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Error(codes.DeadlineExceeded, "client too slow")
+	})
+	intercept(nil, ss, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	})
+
+	entries := ring.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if e := entries[0]; e.Level() != logger.LevelWarn || fields(e)["stream"] != true {
+		t.Errorf("unexpected entry: %s %v", e.Level(), e.Fields())
+	}
+}
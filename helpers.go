@@ -1,22 +1,25 @@
 package logger
 
 import (
-	"fmt"
 	"time"
 )
 
 func getCurrentDate() string {
-	now := time.Now()
-	return now.Format("2006/01/02")
+	return formatDate(time.Now())
 }
 func getCurrentTime() string {
-	now := time.Now()
-	return now.Format("15:04:05")
+	return formatTime(time.Now())
+}
+func formatDate(t time.Time) string {
+	return t.Format("2006/01/02")
+}
+func formatTime(t time.Time) string {
+	return t.Format("15:04:05")
 }
 func formatText(style, text string) string {
-	return fmt.Sprintf("%s%s%s", style, text, reset)
+	return Style(style).render(text)
 }
 
 func formatTextExt(style, style2, text string) string {
-	return fmt.Sprintf("%s%s%s%s", style, style2, text, reset)
+	return Style(style + style2).render(text)
 }
@@ -0,0 +1,61 @@
+package logger
+
+// Hook is called with every entry a logger emits, e.g. to forward errors to
+// Sentry or Slack or to count them in a metric. Unlike a Sink it is added
+// to a running logger with AddHook and can be limited to some levels.
+type Hook interface {
+	Fire(e Entry) error
+}
+
+// HookFunc adapts a function to Hook.
+type HookFunc func(e Entry) error
+
+func (f HookFunc) Fire(e Entry) error {
+	return f(e)
+}
+
+type levelHook struct {
+	h      Hook
+	levels []Level
+}
+
+func (h levelHook) fires(lv Level) bool {
+	if len(h.levels) == 0 {
+		return true
+	}
+	for _, l := range h.levels {
+		if l == lv {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHook registers h for the entries of the given levels, all of them when
+// none are given. Hooks are logger-wide: one added to a logger derived with
+// Tag, With, Named or Group also fires for its parent and siblings, so
+// register them on the logger returned by New. Hooks run in order after the
+// console output; their errors are only reported in debug mode. Nop
+// ignores them.
+func (l *Logger) AddHook(h Hook, levels ...Level) {
+	if l.nop {
+		return
+	}
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.entry = append(l.hooks.entry, levelHook{h: h, levels: levels})
+}
+
+func (l *Logger) fireHooks(e Entry) {
+	l.hooks.mu.RLock()
+	hooks := l.hooks.entry
+	l.hooks.mu.RUnlock()
+	for _, h := range hooks {
+		if !h.fires(e.level) {
+			continue
+		}
+		if err := h.h.Fire(e); err != nil && l.c.IsDebugMode {
+			debug("firing hook err: ", err)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pecet3/logger"
+)
+
+const redacted = "[REDACTED]"
+
+// Capture logs the headers and bodies of requests and responses in a Debug
+// entry following the access log entry. Nothing is captured unless the
+// logger has Debug enabled.
+type Capture struct {
+	// MaxBytes limits each captured body, 4 KiB by default.
+	MaxBytes int
+	// ContentTypes lists the media types whose bodies are captured, e.g.
+	// "application/json" or "text/*". JSON, forms and text by default.
+	ContentTypes []string
+	// Headers captures request and response headers too.
+	Headers bool
+	// RedactHeaders are replaced by [REDACTED], Authorization, Cookie and
+	// similar credentials by default.
+	RedactHeaders []string
+	// RedactKeys are JSON keys and form fields replaced by [REDACTED],
+	// password, token and secret by default. Matching is case-insensitive.
+	RedactKeys []string
+
+	once     sync.Once
+	headers  map[string]bool
+	keys     map[string]bool
+	keysJSON *regexp.Regexp
+}
+
+var (
+	defaultContentTypes  = []string{"application/json", "application/*+json", "application/x-www-form-urlencoded", "text/*"}
+	defaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+	defaultRedactKeys    = []string{"password", "token", "secret", "access_token", "refresh_token", "api_key"}
+)
+
+func (c *Capture) init() {
+	c.once.Do(func() {
+		if c.MaxBytes <= 0 {
+			c.MaxBytes = 4 << 10
+		}
+		if c.ContentTypes == nil {
+			c.ContentTypes = defaultContentTypes
+		}
+		if c.RedactHeaders == nil {
+			c.RedactHeaders = defaultRedactHeaders
+		}
+		if c.RedactKeys == nil {
+			c.RedactKeys = defaultRedactKeys
+		}
+		c.headers = make(map[string]bool)
+		for _, h := range c.RedactHeaders {
+			c.headers[http.CanonicalHeaderKey(h)] = true
+		}
+		c.keys = make(map[string]bool)
+		quoted := make([]string, len(c.RedactKeys))
+		for i, k := range c.RedactKeys {
+			c.keys[strings.ToLower(k)] = true
+			quoted[i] = regexp.QuoteMeta(k)
+		}
+		// Used on bodies that no longer parse after being cut at MaxBytes.
+		c.keysJSON = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+	})
+}
+
+func (c *Capture) allowed(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.ContentTypes {
+		if allowed == mt {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(mt, prefix) {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(allowed, "application/*"); ok && strings.HasPrefix(mt, "application/") && strings.HasSuffix(mt, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Capture) redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if c.headers[name] {
+			out[name] = []string{redacted}
+		}
+	}
+	return out
+}
+
+func (c *Capture) redactBody(contentType string, body []byte, truncated bool) string {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil || truncated {
+			return c.redactFormPairs(string(body))
+		}
+		for k := range values {
+			if c.keys[strings.ToLower(k)] {
+				values[k] = []string{redacted}
+			}
+		}
+		return values.Encode()
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		var v interface{}
+		if !truncated && json.Unmarshal(body, &v) == nil {
+			if b, err := json.Marshal(c.redactJSON(v)); err == nil {
+				return string(b)
+			}
+		}
+		return c.keysJSON.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+	}
+	return string(body)
+}
+
+// redactFormPairs redacts by key a form body that does not parse, e.g. one
+// cut at MaxBytes in the middle of an escape, keeping the other pairs as
+// they are.
+func (c *Capture) redactFormPairs(body string) string {
+	pairs := strings.FieldsFunc(body, func(r rune) bool { return r == '&' || r == ';' })
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if c.keys[strings.ToLower(key)] {
+			pairs[i] = url.QueryEscape(key) + "=" + redacted
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func (c *Capture) redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if c.keys[strings.ToLower(k)] {
+				v[k] = redacted
+				continue
+			}
+			v[k] = c.redactJSON(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = c.redactJSON(item)
+		}
+	}
+	return v
+}
+
+// limitedBuffer keeps the first max bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type captureBody struct {
+	io.Reader
+	io.Closer
+}
+
+// captured is the Debug entry of a request, see Capture.
+type captured struct {
+	c                       *Capture
+	req, resp               *limitedBuffer
+	reqType, respType       string
+	reqHeaders, respHeaders http.Header
+}
+
+func (c *Capture) start(r *http.Request) *captured {
+	c.init()
+	cp := &captured{c: c, reqType: r.Header.Get("Content-Type")}
+	if c.Headers {
+		cp.reqHeaders = c.redactHeaders(r.Header)
+	}
+	if r.Body != nil && r.Body != http.NoBody && c.allowed(cp.reqType) {
+		cp.req = &limitedBuffer{max: c.MaxBytes}
+		r.Body = captureBody{Reader: io.TeeReader(r.Body, cp.req), Closer: r.Body}
+	}
+	cp.resp = &limitedBuffer{max: c.MaxBytes}
+	return cp
+}
+
+func (cp *captured) MarshalLog(enc logger.FieldEncoder) {
+	if cp.reqHeaders != nil {
+		enc.AddObject("request_headers", headerFields(cp.reqHeaders))
+	}
+	if cp.req != nil && cp.req.Len() > 0 {
+		enc.AddString("request_body", cp.c.redactBody(cp.reqType, cp.req.Bytes(), cp.req.truncated))
+		if cp.req.truncated {
+			enc.AddBool("request_body_truncated", true)
+		}
+	}
+	if cp.respHeaders != nil {
+		enc.AddObject("response_headers", headerFields(cp.respHeaders))
+	}
+	if cp.resp != nil && cp.resp.Len() > 0 {
+		enc.AddString("response_body", cp.c.redactBody(cp.respType, cp.resp.Bytes(), cp.resp.truncated))
+		if cp.resp.truncated {
+			enc.AddBool("response_body_truncated", true)
+		}
+	}
+}
+
+// finish is called once the handler returned, with the response headers as
+// they were sent.
+func (cp *captured) finish(h http.Header) {
+	cp.respType = h.Get("Content-Type")
+	if cp.respType == "" && cp.resp.Len() > 0 {
+		cp.respType = http.DetectContentType(cp.resp.Bytes())
+	}
+	if !cp.c.allowed(cp.respType) {
+		cp.resp = nil
+	}
+	if cp.c.Headers {
+		cp.respHeaders = cp.c.redactHeaders(h)
+	}
+}
+
+type headerFields http.Header
+
+func (h headerFields) MarshalLog(enc logger.FieldEncoder) {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		enc.AddString(name, strings.Join(h[name], ", "))
+	}
+}
@@ -0,0 +1,102 @@
+package httplog
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the address of the client that sent r. Proxy headers
+// (Forwarded, X-Forwarded-For) are only honoured when the request comes
+// from a trusted proxy, and the forwarding chain is walked from the nearest
+// hop until an untrusted address is found. A hop that is not an address,
+// such as "unknown", ends the walk: what comes before it was sent by the
+// client.
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	return ClientIPCloudflare(r, trusted, nil)
+}
+
+// ClientIPCloudflare is ClientIP for servers behind Cloudflare, whose edge
+// ranges are listed in cloudflare: CF-Connecting-IP is only believed when
+// the peer is in them, and then takes precedence over the other headers.
+func ClientIPCloudflare(r *http.Request, trusted, cloudflare []netip.Prefix) string {
+	peer, ok := parseHost(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if isTrusted(peer, cloudflare) {
+		if ip, ok := parseHost(r.Header.Get("CF-Connecting-IP")); ok {
+			return ip.String()
+		}
+	}
+	if !isTrusted(peer, trusted) {
+		return peer.String()
+	}
+
+	hops := forwardedFor(r.Header.Values("Forwarded"))
+	if len(hops) == 0 {
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			for _, hop := range strings.Split(v, ",") {
+				ip, _ := parseHost(hop)
+				hops = append(hops, ip)
+			}
+		}
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !hops[i].IsValid() {
+			return client.String()
+		}
+		client = hops[i]
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// forwardedFor returns the for= addresses of RFC 7239 Forwarded headers,
+// with an invalid address for those that do not parse.
+func forwardedFor(values []string) []netip.Addr {
+	var hops []netip.Addr
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(key, "for") {
+					continue
+				}
+				ip, _ := parseHost(strings.Trim(value, `"`))
+				hops = append(hops, ip)
+			}
+		}
+	}
+	return hops
+}
+
+// parseHost parses an address with an optional port, e.g. "192.0.2.1:80"
+// or "[2001:db8::1]:443".
+func parseHost(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return netip.Addr{}, false
+	}
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	ip, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
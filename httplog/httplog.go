@@ -0,0 +1,272 @@
+// Package httplog provides net/http middleware writing an access log entry
+// for every request.
+package httplog
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pecet3/logger"
+)
+
+// Access describes a handled request. It is attached to the access log
+// entry as fields and passed to observers.
+type Access struct {
+	Method     string
+	Path       string
+	Route      string // ServeMux pattern, when the request was routed by one
+	Status     int
+	Bytes      int64
+	Duration   time.Duration
+	RemoteAddr string
+	ClientIP   string // see ClientIP
+	UserAgent  string
+	TraceID    string
+
+	enricher Enricher
+}
+
+func (a Access) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddString("method", a.Method)
+	enc.AddString("path", a.Path)
+	if a.Route != "" {
+		enc.AddString("route", a.Route)
+	}
+	enc.AddInt("status", int64(a.Status))
+	enc.AddInt("bytes", a.Bytes)
+	enc.AddDuration("duration", a.Duration)
+	enc.AddString("remote", a.RemoteAddr)
+	if a.ClientIP != "" {
+		enc.AddString("client_ip", a.ClientIP)
+	}
+	if a.UserAgent != "" {
+		enc.AddString("user_agent", a.UserAgent)
+	}
+	if a.TraceID != "" {
+		enc.AddString("trace_id", a.TraceID)
+	}
+	if a.enricher != nil {
+		if ip, err := netip.ParseAddr(a.ClientIP); err == nil {
+			a.enricher.Enrich(ip, enc)
+		}
+	}
+}
+
+// Enricher adds fields derived from the client IP to access log entries,
+// e.g. the country and ASN found in a GeoIP database.
+type Enricher interface {
+	Enrich(ip netip.Addr, enc logger.FieldEncoder)
+}
+
+type EnricherFunc func(ip netip.Addr, enc logger.FieldEncoder)
+
+func (f EnricherFunc) Enrich(ip netip.Addr, enc logger.FieldEncoder) {
+	f(ip, enc)
+}
+
+// Observer is notified of every handled request, e.g. to record metrics
+// next to the access log.
+type Observer interface {
+	Observe(r *http.Request, a Access)
+}
+
+// Rule overrides the logging of the requests whose path matches Pattern:
+// an exact path, a path.Match glob such as "/static/*.css", or a prefix when
+// it ends with "/*" (e.g. "/webhooks/*"). Observers still see every request.
+type Rule struct {
+	Pattern string
+	// Skip drops the access log entry.
+	Skip bool
+	// SampleRate keeps only the given fraction of entries, e.g. 0.01.
+	// Failed requests (5xx) are always logged.
+	SampleRate float64
+	// Level, when set, is used instead of the status based level.
+	Level *logger.Level
+	// Capture, when set, is used instead of Options.Capture.
+	Capture *Capture
+}
+
+func (r *Rule) match(p string) bool {
+	if prefix, ok := strings.CutSuffix(r.Pattern, "/*"); ok {
+		return p == prefix || strings.HasPrefix(p, prefix+"/")
+	}
+	ok, _ := path.Match(r.Pattern, p)
+	return ok
+}
+
+type Options struct {
+	// TraceID extracts the trace ID of a request, read from the W3C
+	// traceparent header by default.
+	TraceID   func(r *http.Request) string
+	Observers []Observer
+	// Rules are checked in order, the first matching one applies.
+	Rules []Rule
+	// Capture logs headers and bodies, see Capture.
+	Capture *Capture
+	// TrustedProxies are the load balancers and proxies whose forwarding
+	// headers are used to resolve the client IP.
+	TrustedProxies []netip.Prefix
+	// CloudflareProxies are the Cloudflare edge ranges whose
+	// CF-Connecting-IP header is believed, see ClientIPCloudflare.
+	CloudflareProxies []netip.Prefix
+	// Enricher, when set, adds fields based on the client IP.
+	Enricher Enricher
+}
+
+func (o *Options) rule(p string) *Rule {
+	for i := range o.Rules {
+		if o.Rules[i].match(p) {
+			return &o.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Middleware logs every request handled by the wrapped handler, at Error
+// level for 5xx responses, Warn for 4xx and Info otherwise.
+func Middleware(l *logger.Logger, opts Options) func(http.Handler) http.Handler {
+	traceID := opts.TraceID
+	if traceID == nil {
+		traceID = TraceParent
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rule := opts.rule(r.URL.Path)
+			capture := opts.Capture
+			if rule != nil && rule.Capture != nil {
+				capture = rule.Capture
+			}
+			rw := &responseWriter{ResponseWriter: w}
+			var cp *captured
+			if capture != nil && l.Enabled(logger.LevelDebug) {
+				cp = capture.start(r)
+				rw.capture = cp.resp
+			}
+			next.ServeHTTP(rw, r)
+
+			a := Access{
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Route:      r.Pattern,
+				Status:     rw.status,
+				Bytes:      rw.bytes,
+				Duration:   time.Since(start),
+				RemoteAddr: r.RemoteAddr,
+				ClientIP:   ClientIPCloudflare(r, opts.TrustedProxies, opts.CloudflareProxies),
+				UserAgent:  r.UserAgent(),
+				TraceID:    traceID(r),
+				enricher:   opts.Enricher,
+			}
+			if a.Status == 0 {
+				a.Status = http.StatusOK
+			}
+			for _, o := range opts.Observers {
+				o.Observe(r, a)
+			}
+
+			lv := logger.LevelInfo
+			switch {
+			case a.Status >= 500:
+				lv = logger.LevelError
+			case a.Status >= 400:
+				lv = logger.LevelWarn
+			}
+			if rule != nil {
+				if rule.Skip {
+					return
+				}
+				if rule.SampleRate > 0 && a.Status < 500 && rand.Float64() >= rule.SampleRate {
+					return
+				}
+				if rule.Level != nil {
+					lv = *rule.Level
+				}
+			}
+			msg := a.Method + " " + a.Path + " " + strconv.Itoa(a.Status)
+			logAt(l, lv, msg, a)
+			if cp != nil {
+				cp.finish(w.Header())
+				l.Debug(msg, cp)
+			}
+		})
+	}
+}
+
+func logAt(l *logger.Logger, lv logger.Level, msg string, a Access) {
+	switch {
+	case lv >= logger.LevelAlert:
+		l.Alert(msg, a)
+	case lv >= logger.LevelError:
+		l.Error(msg, a)
+	case lv >= logger.LevelWarn:
+		l.Warn(msg, a)
+	case lv >= logger.LevelInfo:
+		l.Info(msg, a)
+	default:
+		l.Debug(msg, a)
+	}
+}
+
+// TraceParent returns the trace ID of a W3C traceparent header, or "".
+func TraceParent(r *http.Request) string {
+	parts := strings.Split(r.Header.Get("traceparent"), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	return parts[1]
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	bytes   int64
+	capture io.Writer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	if w.capture != nil {
+		w.capture.Write(b[:n])
+	}
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("httplog: response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,205 @@
+package httplog_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+	"github.com/pecet3/logger/httplog"
+)
+
+type observerFunc func(r *http.Request, a httplog.Access)
+
+func (f observerFunc) Observe(r *http.Request, a httplog.Access) { f(r, a) }
+
+func TestMiddleware(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})
+	mux.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	var seen []httplog.Access
+	h := httplog.Middleware(l, httplog.Options{
+		Observers: []httplog.Observer{observerFunc(func(r *http.Request, a httplog.Access) {
+			seen = append(seen, a)
+		})},
+	})(mux)
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+
+	entries := ring.Entries()
+	if len(entries) != 2 || len(seen) != 2 {
+		t.Fatalf("got %d entries and %d observations, want 2", len(entries), len(seen))
+	}
+	if entries[0].Level() != logger.LevelInfo || entries[0].Message() != "GET /users/7 200" {
+		t.Errorf("unexpected entry: %v %q", entries[0].Level(), entries[0].Message())
+	}
+	if a := seen[0]; a.Route != "GET /users/{id}" || a.Bytes != 5 || a.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected access: %+v", a)
+	}
+	if entries[1].Level() != logger.LevelError || seen[1].Status != http.StatusBadGateway {
+		t.Errorf("5xx should be logged as an error, got %v %d", entries[1].Level(), seen[1].Status)
+	}
+}
+
+func TestMiddleware_Rules(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	debug := logger.LevelDebug
+	h := httplog.Middleware(l, httplog.Options{
+		Rules: []httplog.Rule{
+			{Pattern: "/healthz", Skip: true},
+			{Pattern: "/static/*", SampleRate: 1e-9},
+			{Pattern: "/webhooks/*", Level: &debug},
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, p := range []string{"/healthz", "/static/app.js", "/static/img/logo.png", "/webhooks/github", "/"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Message() != "GET /webhooks/github 200" || entries[0].Level() != logger.LevelDebug {
+		t.Errorf("webhook entry: %v %q", entries[0].Level(), entries[0].Message())
+	}
+}
+
+func TestMiddleware_Capture(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	h := httplog.Middleware(l, httplog.Options{
+		Rules: []httplog.Rule{
+			{Pattern: "/webhooks/*", Capture: &httplog.Capture{MaxBytes: 64, Headers: true}},
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"ok":true,"token":"abc"}`)
+	}))
+
+	req := httptest.NewRequest("POST", "/webhooks/stripe", strings.NewReader(`{"event":"paid","secret":"s3cr3t"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer xyz")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/other", strings.NewReader(`{}`)))
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want access, capture and access", len(entries))
+	}
+	b, _ := json.Marshal(entries[1])
+	got := string(b)
+	for _, want := range []string{
+		`"level":"debug"`,
+		`"Authorization":"[REDACTED]"`,
+		`"request_body":"{\"event\":\"paid\",\"secret\":\"[REDACTED]\"}"`,
+		`"response_body":"{\"ok\":true,\"token\":\"[REDACTED]\"}"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("capture entry %s\ndoes not contain %s", got, want)
+		}
+	}
+	if strings.Contains(got, "s3cr3t") || strings.Contains(got, "xyz") {
+		t.Error("secrets leaked into the capture entry")
+	}
+}
+
+func TestMiddleware_CaptureMalformedForm(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	h := httplog.Middleware(l, httplog.Options{
+		Rules: []httplog.Rule{{Pattern: "/*", Capture: &httplog.Capture{MaxBytes: 21}}},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+
+	for _, body := range []string{
+		"user=a;password=hunter2",
+		"a=1&password=hunter2&x=%zz",
+		"password=hunter2&x=%41%41", // cut at MaxBytes inside "%41"
+	} {
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 6 {
+		t.Fatalf("got %d entries, want an access and a capture entry per request", len(entries))
+	}
+	for _, e := range entries {
+		b, _ := json.Marshal(e)
+		if strings.Contains(string(b), "hunter2") {
+			t.Errorf("the password leaked: %s", b)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")}
+	tests := []struct {
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"203.0.113.5:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.5"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.2.3.4, 10.0.0.7"}, "1.2.3.4"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 10.0.0.7"}, "1.2.3.4"},
+		{"10.0.0.1:1234", map[string]string{"Forwarded": `for="[2001:db8::1]:4711";proto=https, for=10.0.0.9`}, "2001:db8::1"},
+		{"[fd00::1]:443", map[string]string{"CF-Connecting-IP": "198.51.100.7"}, "fd00::1"},
+		{"173.245.48.9:443", map[string]string{"CF-Connecting-IP": "198.51.100.7"}, "198.51.100.7"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "6.6.6.6, unknown"}, "10.0.0.1"},
+		{"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "6.6.6.6, _hidden, 10.0.0.7"}, "10.0.0.7"},
+		{"10.0.0.1:1234", map[string]string{"Forwarded": "for=6.6.6.6, for=unknown"}, "10.0.0.1"},
+		{"10.0.0.1:1234", nil, "10.0.0.1"},
+	}
+	cloudflare := []netip.Prefix{netip.MustParsePrefix("173.245.48.0/20")}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remote
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		if got := httplog.ClientIPCloudflare(r, trusted, cloudflare); got != tt.want {
+			t.Errorf("%s %v: got %s, want %s", tt.remote, tt.headers, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware_Enricher(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	h := httplog.Middleware(l, httplog.Options{
+		Enricher: httplog.EnricherFunc(func(ip netip.Addr, enc logger.FieldEncoder) {
+			if ip.Is4() {
+				enc.AddString("country", "PL")
+			}
+		}),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "192.0.2.10:5555"
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	entries, err := logger.Query(ring, `fields.country == "PL" && fields.client_ip == "192.0.2.10"`).Entries()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected an enriched entry, got %d (%v)", len(entries), err)
+	}
+}
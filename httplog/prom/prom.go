@@ -0,0 +1,53 @@
+// Package prom records the request latencies seen by httplog.Middleware in
+// a Prometheus histogram, with trace IDs attached as exemplars.
+package prom
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/pecet3/logger/httplog"
+)
+
+// Latency is an httplog.Observer feeding the
+// http_request_duration_seconds histogram, labelled by method, route and
+// status code.
+type Latency struct {
+	hist *prometheus.HistogramVec
+}
+
+var _ httplog.Observer = (*Latency)(nil)
+
+// NewLatency registers the histogram with reg, using the default buckets
+// when none are given.
+func NewLatency(reg prometheus.Registerer, buckets ...float64) (*Latency, error) {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	hist := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests.",
+		Buckets: buckets,
+	}, []string{"method", "route", "code"})
+	if err := reg.Register(hist); err != nil {
+		return nil, err
+	}
+	return &Latency{hist: hist}, nil
+}
+
+func (l *Latency) Observe(r *http.Request, a httplog.Access) {
+	route := a.Route
+	if route == "" {
+		route = "unmatched"
+	}
+	obs := l.hist.WithLabelValues(a.Method, route, strconv.Itoa(a.Status))
+	if a.TraceID != "" {
+		if eo, ok := obs.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(a.Duration.Seconds(), prometheus.Labels{"trace_id": a.TraceID})
+			return
+		}
+	}
+	obs.Observe(a.Duration.Seconds())
+}
@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// JobRun reports one run of a scheduled job: a "job started" entry when it
+// is created, "job attempt failed" for every retry and "job succeeded" or
+// "job failed" when it ends. All of them carry the job name, a run_id and
+// the attempt number, and the final one the duration and the counters of
+// the job across runs.
+type JobRun struct {
+	l     *Logger
+	name  string
+	id    string
+	start time.Time
+
+	mu      sync.Mutex
+	attempt int
+	ended   bool
+}
+
+// JobSummary aggregates the runs of a job on a logger and the loggers
+// derived from it.
+type JobSummary struct {
+	Name      string
+	Runs      int
+	Succeeded int
+	Failed    int
+	// ConsecutiveFailures counts the runs that failed since the last success.
+	ConsecutiveFailures int
+	LastDuration        time.Duration
+	TotalDuration       time.Duration
+	LastSuccess         time.Time
+	LastFailure         time.Time
+}
+
+func (s JobSummary) MarshalLog(enc FieldEncoder) {
+	enc.AddString("job", s.Name)
+	enc.AddInt("runs", int64(s.Runs))
+	enc.AddInt("succeeded", int64(s.Succeeded))
+	enc.AddInt("failed", int64(s.Failed))
+	if s.ConsecutiveFailures > 0 {
+		enc.AddInt("consecutive_failures", int64(s.ConsecutiveFailures))
+	}
+	if s.Runs > 0 {
+		enc.AddDuration("avg_duration", s.TotalDuration/time.Duration(s.Runs))
+	}
+	if !s.LastSuccess.IsZero() {
+		enc.AddTime("last_success", s.LastSuccess)
+	}
+}
+
+// jobStats holds the summaries of the jobs run through a logger.
+type jobStats struct {
+	mu   sync.Mutex
+	jobs map[string]*JobSummary
+}
+
+func (s *jobStats) record(name string, d time.Duration, err error, now time.Time) JobSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]*JobSummary)
+	}
+	js := s.jobs[name]
+	if js == nil {
+		js = &JobSummary{Name: name}
+		s.jobs[name] = js
+	}
+	js.Runs++
+	js.LastDuration = d
+	js.TotalDuration += d
+	if err != nil {
+		js.Failed++
+		js.ConsecutiveFailures++
+		js.LastFailure = now
+	} else {
+		js.Succeeded++
+		js.ConsecutiveFailures = 0
+		js.LastSuccess = now
+	}
+	return *js
+}
+
+func (l *Logger) JobRun(name string) *JobRun {
+	r := &JobRun{l: l, name: name, id: newRunID(), start: time.Now(), attempt: 1}
+	if l.enabled(LevelInfo) {
+		l.log(makeEntry(LevelInfo, "job started", marshalFields(r.event(nil)), nil))
+	}
+	return r
+}
+
+// ID returns the run_id of the run.
+func (r *JobRun) ID() string {
+	return r.id
+}
+
+// Attempt returns the number of the current attempt, starting at 1.
+func (r *JobRun) Attempt() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.attempt
+}
+
+// Retry logs a Warn entry for the failed attempt and starts the next one.
+func (r *JobRun) Retry(err error) {
+	r.mu.Lock()
+	if r.ended {
+		r.mu.Unlock()
+		return
+	}
+	ev := r.event(err)
+	r.attempt++
+	r.mu.Unlock()
+	if r.l.enabled(LevelWarn) {
+		r.l.log(makeEntry(LevelWarn, "job attempt failed", marshalFields(ev), nil))
+	}
+}
+
+// End finishes the run, logging "job succeeded" at Info when err is nil and
+// "job failed" at Error otherwise. Only the first call logs, so it can be
+// deferred.
+func (r *JobRun) End(err error) {
+	r.mu.Lock()
+	if r.ended {
+		r.mu.Unlock()
+		return
+	}
+	r.ended = true
+	ev := r.event(err)
+	r.mu.Unlock()
+
+	now := time.Now()
+	ev.duration = now.Sub(r.start)
+	ev.summary = r.l.jobs.record(r.name, ev.duration, err, now)
+	level, message := LevelInfo, "job succeeded"
+	if err != nil {
+		level, message = LevelError, "job failed"
+	}
+	if r.l.enabled(level) {
+		r.l.log(makeEntry(level, message, marshalFields(ev), nil))
+	}
+}
+
+// JobSummary returns the summary of the runs of the named job, the zero
+// value with Name set when it never ran.
+func (l *Logger) JobSummary(name string) JobSummary {
+	l.jobs.mu.Lock()
+	defer l.jobs.mu.Unlock()
+	if js := l.jobs.jobs[name]; js != nil {
+		return *js
+	}
+	return JobSummary{Name: name}
+}
+
+func (r *JobRun) event(err error) jobEvent {
+	return jobEvent{name: r.name, id: r.id, attempt: r.attempt, err: err}
+}
+
+type jobEvent struct {
+	name     string
+	id       string
+	attempt  int
+	err      error
+	duration time.Duration
+	summary  JobSummary
+}
+
+func (ev jobEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("job", ev.name)
+	enc.AddString("run_id", ev.id)
+	enc.AddInt("attempt", int64(ev.attempt))
+	if ev.err != nil {
+		enc.AddString("error", ev.err.Error())
+	}
+	if ev.summary.Runs == 0 {
+		return
+	}
+	enc.AddDuration("duration", ev.duration)
+	enc.AddInt("runs", int64(ev.summary.Runs))
+	enc.AddInt("failures", int64(ev.summary.Failed))
+	if ev.summary.ConsecutiveFailures > 0 {
+		enc.AddInt("consecutive_failures", int64(ev.summary.ConsecutiveFailures))
+	}
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
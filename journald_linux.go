@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+// Journal is a Sink writing entries to the systemd journal over its native
+// protocol. Fields, flattened into dotted keys, become journal fields with
+// upper-case names (user.id is USER_ID), next to MESSAGE, PRIORITY, the
+// CODE_* fields of the caller and LOGGER_NAME.
+type Journal struct {
+	identifier string
+	conn       *net.UnixConn
+}
+
+// NewJournal connects to journald; identifier is the SYSLOG_IDENTIFIER of
+// the entries and defaults to the program name.
+func NewJournal(identifier string) (*Journal, error) {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("journal: %w", err)
+	}
+	return &Journal{identifier: identifier, conn: conn}, nil
+}
+
+func (s *Journal) WriteEntry(e Entry) error {
+	var b bytes.Buffer
+	journalField(&b, "MESSAGE", e.message)
+	journalField(&b, "PRIORITY", strconv.Itoa(syslogSeverity(e.level)))
+	journalField(&b, "SYSLOG_IDENTIFIER", s.identifier)
+	if c := e.Caller(); !c.IsZero() {
+		journalField(&b, "CODE_FILE", c.File)
+		journalField(&b, "CODE_LINE", strconv.Itoa(c.Line))
+		journalField(&b, "CODE_FUNC", c.Function)
+	}
+	if e.name != "" {
+		journalField(&b, "LOGGER_NAME", e.name)
+	}
+	if len(e.tags) > 0 {
+		journalField(&b, "LOGGER_TAGS", strings.Join(e.tags, ","))
+	}
+	walkFields("", e.fields, func(key string, v interface{}) {
+		if name := journalFieldName(key); name != "" {
+			journalField(&b, name, rawFieldValue(v))
+		}
+	})
+
+	_, err := s.conn.Write(b.Bytes())
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		err = s.writeLarge(b.Bytes())
+	}
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+	return nil
+}
+
+// writeLarge passes an entry too large for a datagram as the descriptor of
+// a removed temporary file, as the protocol allows.
+func (s *Journal) writeLarge(data []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "journal.*")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	_, _, err = s.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), nil)
+	return err
+}
+
+func (s *Journal) Close() error {
+	return s.conn.Close()
+}
+
+// journalField appends a field, in the binary form when value spans lines.
+func journalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteString("=" + value + "\n")
+		return
+	}
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// journalFieldName turns key into a journal field name: upper-case letters,
+// digits and underscores, not starting with an underscore or digit.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the version of the serialized Entry layout read by this
+// package. Entries without a version are treated as version 1. Version 2
+// added message dictionaries; entries not using one are still written as
+// version 1, so older readers keep decoding them.
+const SchemaVersion = 2
+
+// entryMigrations upgrade a decoded entry object from the version used as
+// key to the next one, so older files stay readable when the layout changes.
+// A nil migration marks a version whose entries are valid in the next one.
+var entryMigrations = map[int]func(raw map[string]json.RawMessage) error{
+	1: nil, // v2 only added tmpl and params
+}
+
+type jsonCaller struct {
+	Function string `json:"function,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+type jsonEntry struct {
+	Version int             `json:"v"`
+	Time    time.Time       `json:"time"`
+	Seq     uint64          `json:"seq,omitempty"`
+	Level   string          `json:"level"`
+	Logger  string          `json:"logger,omitempty"`
+	Message string          `json:"msg"`
+	Tags    []string        `json:"tags,omitempty"`
+	Caller  *jsonCaller     `json:"caller,omitempty"`
+	Fields  json.RawMessage `json:"fields,omitempty"`
+
+	// Template and Params replace Message in files with a message
+	// dictionary, see dictEncoder.
+	Template uint32   `json:"tmpl,omitempty"`
+	Params   []string `json:"params,omitempty"`
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return e.marshalJSON(0, nil)
+}
+
+func (e Entry) marshalJSON(tmpl uint32, params []string) ([]byte, error) {
+	je := jsonEntry{
+		Version: 1,
+		Time:    e.time,
+		Seq:     e.seq,
+		Level:   e.level.String(),
+		Logger:  e.name,
+		Message: e.message,
+		Tags:    e.tags,
+	}
+	if tmpl != 0 {
+		// Readers of v1 would decode an empty message.
+		je.Version, je.Message, je.Template, je.Params = 2, "", tmpl, params
+	}
+	caller := e.Caller()
+	if !caller.IsZero() {
+		je.Caller = &jsonCaller{
+			Function: caller.Function,
+			File:     caller.File,
+			Line:     caller.Line,
+		}
+	}
+	if len(e.fields) > 0 {
+		fields, err := marshalFieldsJSON(e.fields)
+		if err != nil {
+			return nil, err
+		}
+		je.Fields = fields
+	}
+	return json.Marshal(je)
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	_, err := e.unmarshalJSON(data)
+	return err
+}
+
+func (e *Entry) unmarshalJSON(data []byte) (jsonEntry, error) {
+	je, err := decodeJSONEntry(data)
+	if err != nil {
+		return je, err
+	}
+	level, err := ParseLevel(je.Level)
+	if err != nil {
+		return je, err
+	}
+	*e = Entry{
+		time:    je.Time,
+		level:   level,
+		message: je.Message,
+		name:    je.Logger,
+		tags:    je.Tags,
+		seq:     je.Seq,
+	}
+	if je.Caller != nil {
+		e.caller = &callerRef{c: Caller{
+			Function: je.Caller.Function,
+			File:     je.Caller.File,
+			Line:     je.Caller.Line,
+		}}
+	}
+	if len(je.Fields) > 0 {
+		fields, err := unmarshalFieldsJSON(je.Fields)
+		if err != nil {
+			return je, err
+		}
+		e.fields = fields
+	}
+	return je, nil
+}
+
+func decodeJSONEntry(data []byte) (jsonEntry, error) {
+	var je jsonEntry
+	if err := json.Unmarshal(data, &je); err != nil {
+		return je, err
+	}
+	if je.Version == 0 {
+		je.Version = 1
+	}
+	if je.Version == SchemaVersion {
+		return je, nil
+	}
+	if je.Version > SchemaVersion {
+		return je, fmt.Errorf("entry schema v%d is newer than supported v%d", je.Version, SchemaVersion)
+	}
+
+	var raw map[string]json.RawMessage
+	for v := je.Version; v < SchemaVersion; v++ {
+		migrate, ok := entryMigrations[v]
+		if !ok {
+			return je, fmt.Errorf("no migration from entry schema v%d", v)
+		}
+		if migrate == nil {
+			continue
+		}
+		if raw == nil {
+			if err := json.Unmarshal(data, &raw); err != nil {
+				return je, err
+			}
+		}
+		if err := migrate(raw); err != nil {
+			return je, fmt.Errorf("migrating entry schema v%d: %w", v, err)
+		}
+	}
+	if raw == nil {
+		je.Version = SchemaVersion
+		return je, nil
+	}
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return je, err
+	}
+	je = jsonEntry{}
+	if err := json.Unmarshal(migrated, &je); err != nil {
+		return je, err
+	}
+	je.Version = SchemaVersion
+	return je, nil
+}
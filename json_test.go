@@ -0,0 +1,132 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+func TestDecodeStream(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Format: logger.FormatJSON, Output: &out})
+	l.Info("first")
+	l.WarnC("second")
+	out.WriteString("\nnot json\n")
+
+	entries, errc := logger.DecodeStream(&out)
+	var got []logger.Entry
+	for e := range entries {
+		got = append(got, e)
+	}
+	err := <-errc
+
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	if got[0].Message() != "first" || got[1].Level() != logger.LevelWarn {
+		t.Errorf("unexpected entries: %v %v", got[0].Message(), got[1].Level())
+	}
+	if got[1].Caller().IsZero() {
+		t.Error("WarnC entry should carry its caller")
+	}
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("expected an error for line 4, got %v", err)
+	}
+}
+
+type testUser struct {
+	id    int64
+	name  string
+	admin bool
+}
+
+func (u testUser) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddInt("id", u.id)
+	enc.AddString("name", u.name)
+	enc.AddBool("admin", u.admin)
+}
+
+type testRequest struct {
+	user testUser
+}
+
+func (r testRequest) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddString("method", "GET")
+	enc.AddObject("user", r.user)
+}
+
+func TestLogMarshaler(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Format: logger.FormatJSON, Output: &out})
+	l.Info("request served", testRequest{user: testUser{id: 7, name: "ada"}})
+
+	want := `"msg":"request served","fields":{"method":"GET","user":{"id":7,"name":"ada","admin":false}}`
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("unexpected JSON output:\n%s", out.String())
+	}
+
+	entries, _ := logger.DecodeStream(&out)
+	e := <-entries
+	fields := e.Fields()
+	if len(fields) != 2 || fields[1].Key != "user" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	user, ok := fields[1].Value.([]logger.Field)
+	if !ok || len(user) != 3 || user[0].Value != int64(7) {
+		t.Errorf("nested fields not decoded in order: %#v", fields[1].Value)
+	}
+}
+
+func TestDecodeStreamOptions_BackfillTime(t *testing.T) {
+	in := `{"level":"info","msg":"2025/01/09 10:11:12 imported from syslog"}` + "\n" +
+		`{"level":"info","msg":"no timestamp here"}` + "\n"
+
+	entries, _ := logger.DecodeStreamOptions(strings.NewReader(in), logger.DecodeOptions{BackfillTime: true})
+	e := <-entries
+	if got := e.Time().Format("2006-01-02 15:04:05"); got != "2025-01-09 10:11:12" {
+		t.Errorf("time not taken from the message: %s", got)
+	}
+	if e.Message() != "imported from syslog" {
+		t.Errorf("timestamp should be removed from the message: %q", e.Message())
+	}
+	if e = <-entries; e.Time().IsZero() {
+		t.Error("entries without a timestamp should get the read time")
+	}
+}
+
+func TestLogger_WithFields(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, Format: logger.FormatJSON})
+	reqLog := l.WithFields(map[string]interface{}{"request_id": "7f3a", "attempt": 2})
+	reqLog.Info("handled", logger.Field{Key: "status", Value: 200}, logger.Fields{"user": testUser{id: 7}})
+	l.Info("unrelated")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := `"fields":{"attempt":2,"request_id":"7f3a","status":200,"user":{"id":7,"name":"","admin":false}}`
+	if !strings.Contains(lines[0], want) {
+		t.Errorf("got %s, want it to contain %s", lines[0], want)
+	}
+	if strings.Contains(lines[1], "fields") {
+		t.Errorf("the parent logger should not get the fields: %s", lines[1])
+	}
+}
+
+func TestLogger_NamedWith(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Name: "api", Output: &out, Format: logger.FormatJSON})
+	httpLog := l.Named("http").With(logger.Field{Key: "port", Value: 8080})
+	httpLog.Named("router").Info("listening")
+	l.Info("ready")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	for _, want := range []string{`"logger":"api.http.router"`, `"fields":{"port":8080}`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("got %s, want it to contain %s", lines[0], want)
+		}
+	}
+	if !strings.Contains(lines[1], `"logger":"api"`) || strings.Contains(lines[1], "port") {
+		t.Errorf("the parent logger should keep its name and fields: %s", lines[1])
+	}
+}
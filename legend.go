@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+)
+
+// Legend prints a key to the console output: the badge of every level in
+// its color, marking the ones below the configured level, followed by the
+// name patterns and tags that have their own style in the theme. Nothing is
+// printed in JSON format.
+func (l *Logger) Legend() {
+	if l.nop || l.c.Format == FormatJSON {
+		return
+	}
+	w, f := l.o.get(LevelInfo)
+	writeLine(w, f.legend(l.level()))
+}
+
+func (f *formatter) legend(min Level) string {
+	t := f.theme
+	var b strings.Builder
+	b.WriteString("Legend")
+
+	levels := make([]Level, 0, len(levelNames))
+	for lv := range levelNames {
+		levels = append(levels, lv)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	for _, lv := range levels {
+		b.WriteString("\n  ")
+		if icon, ok := f.icons[lv]; ok {
+			b.WriteString(t.Levels[lv].render(icon) + " ")
+		}
+		b.WriteString("[" + (t.Badge + t.Levels[lv]).render(f.badge(lv)) + "] " + lv.String())
+		if lv < min {
+			b.WriteString(" (off)")
+		}
+	}
+
+	if len(t.Names) > 0 {
+		b.WriteString("\n  names:")
+		for _, pattern := range sortedStyles(t.Names) {
+			b.WriteString(" " + t.Names[pattern].render(pattern))
+		}
+	}
+	if len(t.Tags) > 0 {
+		b.WriteString("\n  tags:")
+		for _, tag := range sortedStyles(t.Tags) {
+			b.WriteString(" " + tagChip(t.tag(tag), tag))
+		}
+	}
+	return b.String()
+}
+
+func sortedStyles(m map[string]Style) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicLevel is a level that can be changed at runtime, e.g. by an admin
+// endpoint, and shared by several loggers through Config.AtomicLevel.
+type AtomicLevel struct {
+	v atomic.Int64
+
+	mu       sync.Mutex
+	watchers []func(old, new Level)
+}
+
+func NewAtomicLevel(lv Level) *AtomicLevel {
+	a := &AtomicLevel{}
+	a.v.Store(int64(lv))
+	return a
+}
+
+func (a *AtomicLevel) Level() Level {
+	return Level(a.v.Load())
+}
+
+// SetLevel changes the level and runs the OnChange callbacks if it differs
+// from the current one. Callbacks run before SetLevel returns, in the order
+// they were registered.
+func (a *AtomicLevel) SetLevel(lv Level) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old := Level(a.v.Swap(int64(lv)))
+	if old == lv {
+		return
+	}
+	for _, fn := range a.watchers {
+		fn(old, lv)
+	}
+}
+
+// OnChange registers fn to be called after every change of the level, so
+// subsystems can react when operators flip levels at runtime. fn must not
+// call SetLevel.
+func (a *AtomicLevel) OnChange(fn func(old, new Level)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.watchers = append(a.watchers, fn)
+}
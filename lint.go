@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxLintReports bounds the messages Lint remembers; once reached, no more
+// messages are checked.
+const maxLintReports = 1000
+
+// LintRule reports what is wrong with a log message, or "" when it is
+// fine.
+type LintRule func(message string) string
+
+// Lint checks log messages against style rules and logs a Warn entry for
+// every message breaking one, once per message and rule. Only the messages
+// of methods such as Info are checked: those of Infof and the other f
+// variants contain formatted values. It is meant for development: set
+// Config.Lint in dev builds and leave it nil in production.
+type Lint struct {
+	// Rules are the checks applied to every message, DefaultLintRules()
+	// when nil.
+	Rules []LintRule
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// DefaultLintRules returns LintNoTrailingPunctuation, LintLowercaseStart
+// and LintNoFormatVerbs.
+func DefaultLintRules() []LintRule {
+	return []LintRule{LintNoTrailingPunctuation, LintLowercaseStart, LintNoFormatVerbs}
+}
+
+// LintNoTrailingPunctuation flags messages ending with ".", "!", "?", ":"
+// or ";". A trailing "..." is allowed.
+func LintNoTrailingPunctuation(msg string) string {
+	msg = strings.TrimRightFunc(msg, unicode.IsSpace)
+	if msg == "" || strings.HasSuffix(msg, "...") {
+		return ""
+	}
+	if strings.ContainsAny(msg[len(msg)-1:], ".!?:;") {
+		return "message ends with punctuation"
+	}
+	return ""
+}
+
+// LintLowercaseStart flags messages starting with a capital letter, unless
+// the first word is an acronym such as "HTTP".
+func LintLowercaseStart(msg string) string {
+	first, n := utf8.DecodeRuneInString(msg)
+	if !unicode.IsUpper(first) {
+		return ""
+	}
+	if second, _ := utf8.DecodeRuneInString(msg[n:]); unicode.IsUpper(second) || unicode.IsDigit(second) {
+		return ""
+	}
+	return "message starts with a capital letter"
+}
+
+// formatVerb matches printf verbs such as %s, %d, %v or %5.2f.
+var formatVerb = regexp.MustCompile(`%[-+#0]?(\d+)?(\.\d+)?[vTtbcdoqxXsefgp]`)
+
+// LintNoFormatVerbs flags messages containing printf verbs, as in
+// Info("user %s not found", id): the arguments are concatenated instead
+// of formatted, so the f variant (Infof) was meant. Percent-encoded bytes
+// such as "%20f" in URLs are not flagged.
+func LintNoFormatVerbs(msg string) string {
+	for _, loc := range formatVerb.FindAllStringIndex(msg, -1) {
+		verb := msg[loc[0]:loc[1]]
+		if loc[1] < len(msg) && isLetter(msg[loc[1]]) && strings.ContainsAny(verb, "0123456789") {
+			continue
+		}
+		return "message contains the format verb " + strconv.Quote(verb) + ", use the f variant such as Infof"
+	}
+	return ""
+}
+
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// LintMaxLength flags messages longer than n characters; details belong in
+// fields.
+func LintMaxLength(n int) LintRule {
+	return func(msg string) string {
+		if utf8.RuneCountInString(msg) > n {
+			return "message is longer than " + strconv.Itoa(n) + " characters"
+		}
+		return ""
+	}
+}
+
+// check returns the problems of e not reported before.
+func (li *Lint) check(e Entry) []string {
+	if e.message == "" || !e.args {
+		return nil
+	}
+	rules := li.Rules
+	if rules == nil {
+		rules = DefaultLintRules()
+	}
+	var problems []string
+	for _, rule := range rules {
+		if p := rule(e.message); p != "" {
+			problems = append(problems, p)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	if li.seen == nil {
+		li.seen = make(map[string]struct{})
+	}
+	fresh := problems[:0]
+	for _, p := range problems {
+		key := p + "\x00" + e.message
+		if _, ok := li.seen[key]; ok || len(li.seen) >= maxLintReports {
+			continue
+		}
+		li.seen[key] = struct{}{}
+		fresh = append(fresh, p)
+	}
+	return fresh
+}
+
+type lintEvent struct {
+	problem string
+	message string
+	caller  Caller
+}
+
+func (ev lintEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("problem", ev.problem)
+	enc.AddString("message", ev.message)
+	if !ev.caller.IsZero() {
+		enc.AddString("caller", ev.caller.Function+":"+strconv.Itoa(ev.caller.Line))
+	}
+}
+
+// lint logs the problems Config.Lint finds in e.
+func (l *Logger) lint(e Entry) {
+	problems := l.c.Lint.check(e)
+	if len(problems) == 0 || !l.enabled(LevelWarn) {
+		return
+	}
+	caller := e.Caller()
+	for _, p := range problems {
+		l.log(makeEntry(LevelWarn, "log message lint", marshalFields(lintEvent{problem: p, message: e.message, caller: caller}), nil))
+	}
+}
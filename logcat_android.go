@@ -0,0 +1,57 @@
+//go:build android && cgo
+
+package logger
+
+/*
+#cgo LDFLAGS: -llog
+#include <stdlib.h>
+#include <android/log.h>
+*/
+import "C"
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Logcat is a Sink forwarding entries to the Android log with the priority
+// matching their level. Tag defaults to the logger name, then "GoLog".
+type Logcat struct {
+	Tag string
+}
+
+var logcatPriorities = map[Level]C.int{
+	LevelDebug: C.ANDROID_LOG_DEBUG,
+	LevelInfo:  C.ANDROID_LOG_INFO,
+	LevelWarn:  C.ANDROID_LOG_WARN,
+	LevelError: C.ANDROID_LOG_ERROR,
+	LevelAlert: C.ANDROID_LOG_FATAL,
+	LevelPanic: C.ANDROID_LOG_FATAL,
+	LevelFatal: C.ANDROID_LOG_FATAL,
+}
+
+func (s Logcat) WriteEntry(e Entry) error {
+	tag := s.Tag
+	if tag == "" {
+		tag = e.name
+	}
+	if tag == "" {
+		tag = "GoLog"
+	}
+	prio, ok := logcatPriorities[e.level]
+	if !ok {
+		prio = C.ANDROID_LOG_INFO
+	}
+	msg := joinFields(e.message, e.fields, nil)
+	caller := e.Caller()
+	if !caller.IsZero() {
+		msg = caller.Function + ":" + strconv.Itoa(caller.Line) + " " + msg
+	}
+
+	ctag := C.CString(tag)
+	defer C.free(unsafe.Pointer(ctag))
+	cmsg := C.CString(msg)
+	defer C.free(unsafe.Pointer(cmsg))
+	C.__android_log_write(prio, ctag, cmsg)
+	return nil
+}
@@ -2,11 +2,12 @@ package logger
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"strconv"
 )
 
-func alert(args ...interface{}) {
+func debug(args ...interface{}) {
 	pc, _, line, _ := runtime.Caller(1)
 	fn := runtime.FuncForPC(pc)
 	fName := fn.Name()
@@ -15,151 +16,160 @@ func alert(args ...interface{}) {
 
 	msg := fmt.Sprint(args...)
 	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, blue, " ALERT"),
+		formatTextExt(bold, blue, " LOGGER DEBUG"),
 		formatTextExt(dim, italic, date),
 		formatText(underline, time),
 		formatText(brightBlue, fName),
 		formatText(bold, strconv.Itoa(line)),
 	)
-	fmt.Println(content)
 	if len(args) > 0 {
-		fmt.Println("↳", formatText(bgBlue, formatTextExt(bold, brightYellow, msg)))
+		content += "\n↳ " + formatText(bgBlue, formatTextExt(bold, brightYellow, msg))
 	}
-
+	writeLine(os.Stdout, content)
 }
-func debug(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	time := getCurrentTime()
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, blue, " LOGGER DEBUG"),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(brightBlue, fName),
-		formatText(bold, strconv.Itoa(line)),
-	)
-	fmt.Println(content)
-	if len(args) > 0 {
-		fmt.Println("↳", formatText(bgBlue, formatTextExt(bold, brightYellow, msg)))
+func logDefault(level Level, withCaller bool, args []interface{}) {
+	if !defaultEnabled(level) {
+		return
 	}
+	var caller *callerRef
+	if withCaller && defaultCaller(level) {
+		caller = captureCaller(3)
+	}
+	writeDefault(newEntry(level, args, caller))
+}
 
+func logDefaultf(level Level, withCaller bool, format string, args []interface{}) {
+	if !defaultEnabled(level) {
+		return
+	}
+	var caller *callerRef
+	if withCaller && defaultCaller(level) {
+		caller = captureCaller(3)
+	}
+	writeDefault(makeEntry(level, fmt.Sprintf(format, args...), nil, caller))
 }
-func Error(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	time := getCurrentTime()
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, red, " ERROR"),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(brightBlue, fName),
-		formatText(bold, strconv.Itoa(line)),
-	)
-	fmt.Println(content)
-	if len(args) > 0 {
-		fmt.Println("↳", formatText(bgRed, formatTextExt(bold, brightYellow, msg)))
+func defaultEnabled(level Level) bool {
+	if l := Default(); l != nil {
+		return l.enabled(level)
 	}
+	return level >= Level(fallbackLevel.Load())
+}
 
+func defaultCaller(level Level) bool {
+	l := Default()
+	return l == nil || level >= l.c.CallerLevel
 }
 
-func Info(args ...interface{}) {
+func writeDefault(e Entry) {
+	if l := Default(); l != nil {
+		l.log(e)
+		return
+	}
+	e.addDynamicFields()
+	writeLine(os.Stdout, stdoutFormatter().console(e))
+}
 
-	date := getCurrentDate()
-	time := getCurrentTime()
+// Alert logs an Alert entry through the default logger, which also hands it
+// to the logger's senders.
+func Alert(args ...interface{}) {
+	if l := Default(); l != nil {
+		l.CallerSkip(1).Alert(args...)
+		return
+	}
+	logDefault(LevelAlert, true, args)
+}
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s %s`,
-		formatTextExt(bold, brightGreen, " INFO "),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(bold, msg),
-	)
-	fmt.Println(content)
+func Error(args ...interface{}) {
+	logDefault(LevelError, true, args)
+}
 
+func Info(args ...interface{}) {
+	logDefault(LevelInfo, false, args)
 }
 
 func InfoC(args ...interface{}) {
+	logDefault(LevelInfo, true, args)
+}
 
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	time := getCurrentTime()
+func Warn(args ...interface{}) {
+	logDefault(LevelWarn, false, args)
+}
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, brightGreen, " INFO "),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(brightBlue, fName),
-		formatText(bold, strconv.Itoa(line)),
-	)
-	fmt.Println(content)
-	if len(args) > 0 {
-		fmt.Println("↳", formatTextExt(bold, brightYellow, msg))
-	}
+func WarnC(args ...interface{}) {
+	logDefault(LevelWarn, true, args)
 }
-func Warn(args ...interface{}) {
 
-	date := getCurrentDate()
-	time := getCurrentTime()
+func Debug(args ...interface{}) {
+	logDefault(LevelDebug, true, args)
+}
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s %s`,
-		formatTextExt(bold, orange, " WARN "),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(bold, msg),
-	)
-	fmt.Println(content)
+// Fatal logs a Fatal entry through the default logger, flushes its sinks
+// and exits the process with status 1.
+func Fatal(args ...interface{}) {
+	logDefault(LevelFatal, true, args)
+	exitDefault()
 }
 
-func WarnC(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	time := getCurrentTime()
+func Fatalf(format string, args ...interface{}) {
+	logDefaultf(LevelFatal, true, format, args)
+	exitDefault()
+}
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, orange, " WARN "),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(brightBlue, fName),
-		formatText(bold, strconv.Itoa(line)),
-	)
-	fmt.Println(content)
-	if len(args) > 0 {
-		fmt.Println("↳", formatTextExt(bold, brightYellow, msg))
+func exitDefault() {
+	if l := Default(); l != nil {
+		l.flushSinks()
 	}
+	os.Exit(1)
+}
 
+// Panic logs a Panic entry through the default logger and panics with its
+// message.
+func Panic(args ...interface{}) {
+	logDefault(LevelPanic, true, args)
+	message, _ := splitArgs(args)
+	panic(message)
 }
-func Debug(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	time := getCurrentTime()
 
-	msg := fmt.Sprint(args...)
-	content := fmt.Sprintf(`[%s] %s %s (%s:%s)`,
-		formatTextExt(bold, magenta, " DBUG "),
-		formatTextExt(dim, italic, date),
-		formatText(underline, time),
-		formatText(brightBlue, fName),
-		formatText(bold, strconv.Itoa(line)),
-	)
-	fmt.Println(content)
-	if len(args) > 0 {
-		fmt.Println("↳", formatTextExt(bold, brightYellow, msg))
+func Panicf(format string, args ...interface{}) {
+	logDefaultf(LevelPanic, true, format, args)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// ErrorErr logs err and the chain of errors it wraps through the default
+// logger, see Logger.ErrorErr.
+func ErrorErr(err error, msg ...interface{}) {
+	if !defaultEnabled(LevelError) {
+		return
 	}
+	var caller *callerRef
+	if defaultCaller(LevelError) {
+		caller = captureCaller(2)
+	}
+	writeDefault(makeErrorEntry(err, msg, caller))
+}
+
+func Errorf(format string, args ...interface{}) {
+	logDefaultf(LevelError, true, format, args)
+}
+
+func Infof(format string, args ...interface{}) {
+	logDefaultf(LevelInfo, false, format, args)
+}
+
+func InfoCf(format string, args ...interface{}) {
+	logDefaultf(LevelInfo, true, format, args)
+}
+
+func Warnf(format string, args ...interface{}) {
+	logDefaultf(LevelWarn, false, format, args)
+}
+
+func WarnCf(format string, args ...interface{}) {
+	logDefaultf(LevelWarn, true, format, args)
+}
+
+func Debugf(format string, args ...interface{}) {
+	logDefaultf(LevelDebug, true, format, args)
 }
@@ -2,10 +2,22 @@ package logger_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pecet3/logger"
 )
@@ -44,3 +56,1517 @@ func TestLogger_Warning(t *testing.T) {
 	fmt.Println(logOutput)
 
 }
+
+func TestLogger_Canonical(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Format: logger.FormatJSON, Output: &out})
+
+	ctx := logger.WithCanonical(context.Background(), l.Canonical("request"))
+	logger.CanonicalFrom(ctx).Set("route", "/users")
+	logger.CanonicalFrom(ctx).Set("status", 200)
+	logger.CanonicalFrom(ctx).Set("status", 500)
+	logger.CanonicalFrom(ctx).Raise(logger.LevelError)
+	logger.CanonicalFrom(ctx).Emit()
+	logger.CanonicalFrom(ctx).Emit()
+	logger.CanonicalFrom(context.Background()).Set("ignored", true)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single summary line, got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `"level":"error"`) ||
+		!strings.Contains(lines[0], `"fields":{"route":"/users","status":500,"duration":`) {
+		t.Errorf("unexpected summary: %s", lines[0])
+	}
+}
+
+func TestRegisterDynamicField(t *testing.T) {
+	calls := 0
+	logger.RegisterDynamicField("goroutines", logger.LevelWarn, func() interface{} {
+		calls++
+		return 3
+	})
+	defer logger.UnregisterDynamicField("goroutines")
+
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Format: logger.FormatJSON, Output: &out})
+	l.Info("below the level")
+	l.Warn("at the level")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+	if strings.Contains(lines[0], "goroutines") || !strings.Contains(lines[1], `"fields":{"goroutines":3}`) {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestLogger_ConcurrentMultilineWrites(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.WarnC("concurrent")
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("got %d lines, want 100", len(lines))
+	}
+	for i := 0; i < len(lines); i += 2 {
+		if strings.HasPrefix(lines[i], "↳") || !strings.HasPrefix(lines[i+1], "↳") {
+			t.Fatalf("header and message lines interleaved at line %d:\n%s\n%s", i, lines[i], lines[i+1])
+		}
+	}
+}
+
+func TestLogger_Markers(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{
+		Output:  &out,
+		Markers: map[logger.Level]string{logger.LevelError: ">>", logger.LevelDebug: ""},
+		Inline:  map[logger.Level]bool{logger.LevelInfo: true},
+	})
+	l.InfoC("served", logger.Field{Key: "status", Value: 200})
+	l.Error("failed")
+	l.Debug("state")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5:\n%s", len(lines), out.String())
+	}
+	if !strings.HasSuffix(lines[0], ") served status=200") {
+		t.Errorf("the Info message should be inline: %q", lines[0])
+	}
+	if lines[2] != ">> failed" || lines[4] != "state" {
+		t.Errorf("unexpected message lines: %q %q", lines[2], lines[4])
+	}
+}
+
+func TestLogger_Icons(t *testing.T) {
+	for _, tt := range []struct {
+		locale, warn, err string
+	}{
+		{"en_US.UTF-8", "⚠ [ WARN ] ", "✖ [ ERROR] "},
+		{"C", "! [ WARN ] ", "x [ ERROR] "},
+	} {
+		t.Setenv("LC_ALL", tt.locale)
+		t.Setenv("WT_SESSION", "")
+		var out bytes.Buffer
+		l := logger.New(&logger.Config{Output: &out, Icons: true})
+		l.Warn("disk almost full")
+		l.Error("timeout")
+		for _, want := range []string{tt.warn, tt.err} {
+			if !strings.Contains("\n"+out.String(), "\n"+want) {
+				t.Errorf("%s: no line starts with %q:\n%s", tt.locale, want, out.String())
+			}
+		}
+	}
+}
+
+func TestLogger_Uptime(t *testing.T) {
+	for _, tt := range []struct {
+		uptime logger.Uptime
+		want   string
+	}{
+		{logger.UptimeOnly, `^\[ INFO \] +\d+\.\d{3}s started$`},
+		{logger.UptimeAlongside, `^\[ INFO \] \d{4}/\d{2}/\d{2} \S+ +\d+\.\d{3}s started$`},
+	} {
+		var out bytes.Buffer
+		l := logger.New(&logger.Config{Output: &out, Uptime: tt.uptime})
+		l.Info("started")
+		if line := strings.TrimSpace(out.String()); !regexp.MustCompile(tt.want).MatchString(line) {
+			t.Errorf("uptime %d: %q does not match %s", tt.uptime, line, tt.want)
+		}
+	}
+}
+
+func TestLogger_BadgeWidth(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{
+		Output: &out,
+		Badges: map[logger.Level]string{
+			logger.LevelWarn:  "\u26a0\ufe0f", // two cells with the variation selector
+			logger.LevelError: "🔥🔥",
+		},
+		BadgeWidth: 3,
+	})
+	l.Warn("disk almost full")
+	l.Error("timeout")
+	l.Info("started")
+
+	for _, want := range []string{"[\u26a0\ufe0f ] ", "[🔥 ] ", "[ IN] "} {
+		if !strings.Contains("\n"+out.String(), "\n"+want) {
+			t.Errorf("no line starts with %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestLogger_SingleLine(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, SingleLine: true})
+	l.WarnC("disk almost full")
+	l.Error("timeout")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], ") disk almost full") || !strings.HasSuffix(lines[1], ") timeout") {
+		t.Errorf("every entry should be on one line:\n%s", out.String())
+	}
+}
+
+func TestLogger_Group(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	op := l.Group()
+	op.Info("checkout started")
+	op.With(logger.Field{Key: "step", Value: 1}).Group().Info("charging card")
+	l.Info("unrelated")
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	group := entries[0].Group()
+	if group == "" || entries[0].Parent() != "" {
+		t.Fatalf("unexpected group %q and parent %q", group, entries[0].Parent())
+	}
+	if e := entries[1]; e.Parent() != group || e.Group() == group || e.Group() == "" {
+		t.Errorf("the sub-group should have its own ID and the first as parent: %v", e.Fields())
+	}
+	if entries[2].Group() != "" {
+		t.Error("entries of l should not be grouped")
+	}
+	linked, err := logger.Query(ring, `fields.group == "`+group+`" || fields.parent == "`+group+`"`).Entries()
+	if err != nil || len(linked) != 2 {
+		t.Errorf("got %d linked entries, err %v", len(linked), err)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var term bytes.Buffer
+	n := &logger.Notifier{Bell: true, Terminal: &term, Interval: time.Minute}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{n}})
+	l.Warn("not notified")
+	l.Error("build failed")
+	l.Error("tests failed")
+	if term.String() != "\a" {
+		t.Errorf("want a single bell, got %q", term.String())
+	}
+}
+
+func TestLogger_Track(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, SlowThreshold: 10 * time.Millisecond})
+	l.Track("fast")()
+	done := l.Track("slow")
+	time.Sleep(15 * time.Millisecond)
+	done()
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Level() != logger.LevelInfo || e.Message() != "fast" || len(e.Fields()) != 1 {
+		t.Errorf("unexpected entry: %s %q %v", e.Level(), e.Message(), e.Fields())
+	}
+	if e := entries[1]; e.Level() != logger.LevelWarn || e.Fields()[1].Key != "slow" {
+		t.Errorf("a slow call should be logged as Warn: %s %v", e.Level(), e.Fields())
+	}
+	if fn := entries[1].Caller().Function; !strings.HasSuffix(fn, "TestLogger_Track") {
+		t.Errorf("the caller should be the caller of Track, got %s", fn)
+	}
+}
+
+func TestLogger_Theme(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, Theme: &logger.Theme{}, Color: logger.ColorAlways})
+	l.Info("plain")
+	l.Error("plain error")
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("empty theme should not emit escape codes: %q", out.String())
+	}
+
+	out.Reset()
+	theme := logger.DefaultTheme()
+	theme.TintMessage = true
+	l = logger.New(&logger.Config{Output: &out, Theme: theme, Color: logger.ColorAlways})
+	l.Warn("tinted")
+	if !strings.Contains(out.String(), string(theme.Levels[logger.LevelWarn])+"tinted") {
+		t.Errorf("message should be tinted with the level color: %q", out.String())
+	}
+
+	out.Reset()
+	theme = logger.DefaultTheme()
+	theme.Names = map[string]logger.Style{"api.*": "\033[34m", "api.auth": "\033[35m"}
+	theme.Tags = map[string]logger.Style{"billing": "\033[42m"}
+	for _, c := range []struct{ name, want string }{
+		{"api.users", "\033[34mapi.users"},
+		{"api.auth", "\033[35mapi.auth"},
+		{"db", string(theme.Name) + "db"},
+	} {
+		out.Reset()
+		logger.New(&logger.Config{Name: c.name, Output: &out, Theme: theme, Color: logger.ColorAlways}).Tag("billing").Info("hi")
+		if !strings.Contains(out.String(), c.want) {
+			t.Errorf("%s: %q does not contain %q", c.name, out.String(), c.want)
+		}
+		if !strings.Contains(out.String(), "\033[42m billing ") {
+			t.Errorf("%s: tag should use its own style: %q", c.name, out.String())
+		}
+	}
+}
+
+func TestProbeTerminal_Light(t *testing.T) {
+	for v, want := range map[string]bool{"0;15": true, "0;default;7": true, "15;0": false, "7;8": false, "": false, "15": false} {
+		t.Setenv("COLORFGBG", v)
+		if got := logger.ProbeTerminal(nil).Light; got != want {
+			t.Errorf("COLORFGBG=%q: got Light %v, want %v", v, got, want)
+		}
+	}
+	if logger.LightTheme().Levels[logger.LevelWarn] == logger.DefaultTheme().Levels[logger.LevelWarn] {
+		t.Error("the light theme should replace the orange Warn color")
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	cleanups []func()
+	logs     []string
+	errors   []string
+}
+
+func (f *fakeT) Name() string            { return "TestFake" }
+func (f *fakeT) Helper()                 {}
+func (f *fakeT) Cleanup(fn func())       { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeT) Log(args ...interface{}) { f.logs = append(f.logs, fmt.Sprint(args...)) }
+func (f *fakeT) Errorf(s string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(s, args...))
+}
+
+func (f *fakeT) finish() {
+	for _, fn := range f.cleanups {
+		fn()
+	}
+}
+
+func TestForTest(t *testing.T) {
+	ft := &fakeT{}
+	l := logger.ForTest(ft)
+	l.Info("hello")
+	l.Error("boom")
+	ft.finish()
+	if len(ft.logs) != 2 || !strings.Contains(ft.logs[0], "TestFake") {
+		t.Errorf("entries should go through t.Log with the test name: %q", ft.logs)
+	}
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "boom") {
+		t.Errorf("logged errors should fail the test: %q", ft.errors)
+	}
+
+	ft = &fakeT{}
+	logger.ForTest(ft, logger.AllowErrors()).Error("expected")
+	ft.finish()
+	if len(ft.errors) != 0 {
+		t.Errorf("AllowErrors should not fail the test: %q", ft.errors)
+	}
+}
+
+func TestNop(t *testing.T) {
+	var l logger.Interface = logger.Nop()
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("discarded")
+		l.Error("discarded")
+	})
+	if allocs != 0 {
+		t.Errorf("Nop logger allocated %v times per call", allocs)
+	}
+}
+
+func TestLogger_Buffer(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: &bytes.Buffer{}, Sinks: []logger.Sink{ring}})
+
+	tx := l.Buffer()
+	tx.Info("reserved seat")
+	tx.Warn("seat was on hold")
+	if ring.Len() != 0 {
+		t.Fatal("buffered entries were logged before Commit")
+	}
+	tx.Commit()
+	tx.Info("after commit")
+
+	dropped := l.Buffer()
+	dropped.Error("payment failed")
+	dropped.Discard()
+	dropped.Info("after discard")
+
+	var got []string
+	for _, e := range ring.Entries() {
+		got = append(got, e.Message())
+	}
+	if strings.Join(got, ",") != "reserved seat,seat was on hold,after commit" {
+		t.Errorf("got entries %v", got)
+	}
+}
+
+func TestLogger_Scope(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Level: logger.LevelInfo, Output: &bytes.Buffer{}, Sinks: []logger.Sink{ring}})
+
+	ok := l.Scope(2)
+	ok.Debug("connecting")
+	ok.Info("synced")
+	ok.End(nil)
+
+	failed := l.Scope(2)
+	failed.Debug("step 1")
+	failed.Debug("step 2")
+	failed.Debug("step 3")
+	failed.End(errors.New("sync failed"))
+
+	var got []string
+	for _, e := range ring.Entries() {
+		got = append(got, e.Level().String()+":"+e.Message())
+	}
+	if strings.Join(got, ",") != "info:synced,debug:step 2,debug:step 3,error:sync failed" {
+		t.Errorf("got entries %v", got)
+	}
+}
+
+func TestLogger_Legend(t *testing.T) {
+	var out bytes.Buffer
+	theme := &logger.Theme{Tags: map[string]logger.Style{"db": "", "retry": ""}, Names: map[string]logger.Style{"api.*": ""}}
+	l := logger.New(&logger.Config{Output: &out, Theme: theme, Color: logger.ColorAlways, Level: logger.LevelInfo})
+	l.Legend()
+	want := "Legend\n" +
+		"  [ DBUG ] debug (off)\n" +
+		"  [ INFO ] info\n" +
+		"  [ WARN ] warn\n" +
+		"  [ ERROR] error\n" +
+		"  [ ALERT] alert\n" +
+		"  [ PANIC] panic\n" +
+		"  [ FATAL] fatal\n" +
+		"  names: api.*\n" +
+		"  tags: #db #retry\n"
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	logger.New(&logger.Config{Output: &out, Format: logger.FormatJSON}).Legend()
+	if out.Len() != 0 {
+		t.Errorf("JSON output should have no legend: %q", out.String())
+	}
+}
+
+func TestSampler(t *testing.T) {
+	ring := logger.NewRing(100)
+	sampler := &logger.Sampler{Rate: 0, High: 0.2, Low: 0.1, Window: time.Hour}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Sampler: sampler})
+	count := func() int { return len(ring.Entries()) }
+
+	for i := 0; i < 10; i++ {
+		l.Info("healthy")
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("got %d entries while healthy, want 0", n)
+	}
+	for i := 0; i < 5; i++ {
+		l.Error("failing")
+	}
+	if !sampler.Elevated() || count() != 5 {
+		t.Fatalf("errors should be kept and raise the error rate: elevated %v, %d entries", sampler.Elevated(), count())
+	}
+
+	// 5 errors in 25 entries is below High but above Low.
+	for i := 0; i < 10; i++ {
+		l.Info("recovering")
+	}
+	if !sampler.Elevated() || count() != 15 {
+		t.Fatalf("entries should be kept until the rate falls to Low: elevated %v, %d entries", sampler.Elevated(), count())
+	}
+	for i := 0; i < 30; i++ {
+		l.Info("recovered")
+	}
+	if sampler.Elevated() {
+		t.Error("sampler should be healthy again")
+	}
+	if n := count(); n != 15+24 {
+		t.Errorf("got %d entries, want %d", n, 15+24)
+	}
+}
+
+func TestLogger_Schema(t *testing.T) {
+	ring := logger.NewRing(200)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	access := l.Schema("http_access", "method", "path", "status")
+	for i := 0; i < 100; i++ {
+		access.Info("GET", fmt.Sprintf("/items/%d", i), 200)
+	}
+	access.Warn("POST", "/items")
+
+	entries := ring.Entries()
+	if len(entries) != 101 {
+		t.Fatalf("got %d entries, want 101", len(entries))
+	}
+	for i, e := range entries[:100] {
+		if f := e.Fields(); e.Message() != "http_access" || len(f) != 3 || f[1].Value != fmt.Sprintf("/items/%d", i) {
+			t.Fatalf("entry %d: got %q %v", i, e.Message(), f)
+		}
+	}
+	if f := entries[100].Fields(); len(f) != 2 || f[1].Key != "path" {
+		t.Errorf("missing values should leave their keys out: %v", f)
+	}
+}
+
+func TestProbeTerminal(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	t.Setenv("COLORTERM", "truecolor")
+	for _, f := range []*os.File{devNull, w} {
+		if term := logger.ProbeTerminal(f); term.IsTerminal || term.Colors != logger.ColorNone {
+			t.Errorf("%s: got %+v, want no terminal", f.Name(), term)
+		}
+	}
+}
+
+func TestStyle_Downsample(t *testing.T) {
+	orange := logger.Hex("#ffa500")
+	for _, c := range []struct {
+		style logger.Style
+		depth logger.ColorDepth
+		want  logger.Style
+	}{
+		{orange, logger.ColorTrueColor, "\033[38;2;255;165;0m"},
+		{orange, logger.Color256, "\033[38;5;214m"},
+		{orange, logger.Color16, "\033[33m"},
+		{logger.BgHex("#00f") + "\033[1m", logger.Color16, "\033[44m\033[1m"},
+		{logger.Fg256(196), logger.Color16, "\033[91m"},
+		{"\033[1;38;2;255;255;255m", logger.Color256, "\033[1;38;5;231m"},
+	} {
+		if got := c.style.Downsample(c.depth); got != c.want {
+			t.Errorf("%q at %v: got %q, want %q", c.style, c.depth, got, c.want)
+		}
+	}
+	if _, err := logger.ParseHex("#12345"); err == nil {
+		t.Error("expected an error for an invalid hex color")
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain":                           "plain",
+		"\033[1;31merror\033[0m done":     "error done",
+		"\033[38;2;255;165;0mwarn\033[0m": "warn",
+		"\033]8;;https://example.com\aexample\033]8;;\a": "example",
+		"\033]8;;x\033\\link\033]8;;\033\\":              "link",
+	} {
+		if got := logger.StripANSI(in); got != want {
+			t.Errorf("StripANSI(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	ring := logger.NewRing(1)
+	logger.New(&logger.Config{Name: "api", Output: io.Discard, Sinks: []logger.Sink{ring}}).Tag("db").Warn("slow", testUser{id: 7, name: "ada"})
+	got := ring.Entries()[0].PlainString()
+	if strings.Contains(got, "\033") || !strings.Contains(got, "[ WARN ]") || !strings.HasSuffix(got, " api #db slow id=7 name=ada admin=false") {
+		t.Errorf("unexpected plain rendering: %q", got)
+	}
+}
+
+func TestEntry_Immutable(t *testing.T) {
+	e := logger.NewEntry(time.Now(), logger.LevelInfo, "created",
+		logger.Field{Key: "user", Value: []logger.Field{{Key: "id", Value: 7}}})
+	fields := e.Fields()
+	fields[0].Value.([]logger.Field)[0].Value = 8
+	fields[0].Key = "changed"
+
+	c := e.Clone()
+	if got := c.Fields(); got[0].Key != "user" || got[0].Value.([]logger.Field)[0].Value != 7 {
+		t.Errorf("entry was changed through its accessor: %v", got)
+	}
+	if c.Seq() != e.Seq() || c.Message() != "created" || c.Level() != logger.LevelInfo {
+		t.Errorf("clone differs: %+v", c)
+	}
+}
+
+func TestAtomicLevel_OnChange(t *testing.T) {
+	ring := logger.NewRing(10)
+	level := logger.NewAtomicLevel(logger.LevelInfo)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, AtomicLevel: level})
+
+	var changes []string
+	level.OnChange(func(old, new logger.Level) {
+		changes = append(changes, old.String()+"->"+new.String())
+	})
+	l.Debug("hidden")
+	level.SetLevel(logger.LevelDebug)
+	level.SetLevel(logger.LevelDebug)
+	l.Debug("shown")
+	level.SetLevel(logger.LevelWarn)
+
+	if got := strings.Join(changes, ","); got != "info->debug,debug->warn" {
+		t.Errorf("got changes %q", got)
+	}
+	if entries := ring.Entries(); len(entries) != 1 || entries[0].Message() != "shown" {
+		t.Errorf("got %d entries, want only the one logged at Debug level", len(entries))
+	}
+}
+
+func TestLogger_SetOutput(t *testing.T) {
+	var out, errOut, moved bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, ErrorOutput: &errOut})
+	child := l.Tag("db")
+	l.Info("started")
+	child.Error("query failed")
+	if strings.Contains(out.String(), "query failed") || !strings.Contains(errOut.String(), "query failed") {
+		t.Errorf("errors should only go to ErrorOutput: out %q, errors %q", out.String(), errOut.String())
+	}
+	if strings.Contains(out.String()+errOut.String(), "\033[") {
+		t.Error("output should not be styled when it is not a terminal")
+	}
+
+	l.SetOutput(&moved)
+	l.SetErrorOutput(nil)
+	child.Info("moved")
+	child.Error("moved error")
+	if got := moved.String(); !strings.Contains(got, "moved") || !strings.Contains(got, "moved error") {
+		t.Errorf("derived loggers should follow SetOutput: %q", got)
+	}
+}
+
+func TestBootstrap_Promote(t *testing.T) {
+	boot := logger.NewBootstrap()
+	boot.Debug("reading config")
+	boot.Error("config: unknown key")
+
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Name: "app", Output: io.Discard, Sinks: []logger.Sink{ring}, Level: logger.LevelInfo})
+	// Hooks logging through the bootstrap must not deadlock the replay.
+	l.AddHook(logger.HookFunc(func(logger.Entry) error {
+		boot.Info("reported")
+		return nil
+	}), logger.LevelError)
+	boot.Promote(l)
+	boot.Info("started")
+
+	entries := ring.Entries()
+	if len(entries) != 3 || entries[0].Message() != "config: unknown key" || entries[1].Message() != "reported" || entries[2].Message() != "started" {
+		t.Fatalf("got %d entries: %v", len(entries), entries)
+	}
+	if entries[0].Name() != "app" || entries[0].Caller().IsZero() {
+		t.Errorf("replayed entries should be formatted by the real logger and keep their caller: %+v", entries[0])
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Level: logger.LevelWarn})
+	child := l.Tag("db")
+	child.Info("hidden")
+	l.SetLevel(logger.LevelInfo)
+	child.Info("shown")
+	if l.GetLevel() != logger.LevelInfo || child.GetLevel() != logger.LevelInfo {
+		t.Errorf("got level %v, want info", l.GetLevel())
+	}
+	if entries := ring.Entries(); len(entries) != 1 || entries[0].Message() != "shown" {
+		t.Errorf("got %d entries, want only the one logged after SetLevel", len(entries))
+	}
+
+	if lv, err := logger.ParseLevel("FATAL"); err != nil || lv != logger.LevelFatal || lv <= logger.LevelAlert {
+		t.Errorf("ParseLevel(FATAL) = %v, %v", lv, err)
+	}
+}
+
+func TestLogger_Go(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	var runs atomic.Int32
+	done := make(chan struct{})
+	l.Go(func() {
+		if runs.Add(1) == 3 {
+			close(done)
+			return
+		}
+		panic("boom")
+	}, logger.Restart(5, time.Millisecond))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the function was not restarted")
+	}
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want one per panic", len(entries))
+	}
+	fields := map[string]interface{}{}
+	for _, f := range entries[1].Fields() {
+		fields[f.Key] = f.Value
+	}
+	if entries[1].Level() != logger.LevelError || fields["panic"] != "boom" || fields["restarts"] != int64(1) ||
+		!strings.Contains(fields["stack"].(string), "TestLogger_Go") {
+		t.Errorf("unexpected panic entry: %v", fields)
+	}
+	if !strings.Contains(entries[1].Caller().Function, "TestLogger_Go") {
+		t.Errorf("caller should be where Go was called: %+v", entries[1].Caller())
+	}
+}
+
+func TestLogger_Pool(t *testing.T) {
+	ring := logger.NewRing(100)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	jobs := make(chan int, 10)
+	jobs <- 1
+	jobs <- 2
+	p := l.Pool("export", logger.PoolOptions{
+		Interval:   10 * time.Millisecond,
+		StallAfter: 20 * time.Millisecond,
+		Queue:      func() int { return len(jobs) },
+	})
+
+	p.Run(1, func() error { return nil })
+	stuck := p.Begin(2)
+	time.Sleep(100 * time.Millisecond)
+	stuck.Done(nil)
+	p.Close()
+
+	counts := map[string]int{}
+	var stats map[string]interface{}
+	for _, e := range ring.Entries() {
+		counts[e.Message()]++
+		if e.Message() == "pool stats" && stats == nil {
+			stats = map[string]interface{}{}
+			for _, f := range e.Fields() {
+				stats[f.Key] = f.Value
+			}
+		}
+	}
+	if counts["item processed"] != 2 || counts["worker stalled"] != 1 || counts["pool stats"] < 2 {
+		t.Errorf("unexpected entries: %v", counts)
+	}
+	if stats["queued"] != int64(2) || stats["busy"] != int64(1) || stats["processed"] != int64(1) {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+}
+
+func TestLogger_JobRun(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+
+	run := l.JobRun("export")
+	run.Retry(errors.New("timeout"))
+	run.End(nil)
+	l.JobRun("export").End(errors.New("disk full"))
+
+	var messages []string
+	for _, e := range ring.Entries() {
+		messages = append(messages, e.Message())
+	}
+	want := []string{"job started", "job attempt failed", "job succeeded", "job started", "job failed"}
+	if strings.Join(messages, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", messages, want)
+	}
+	fields := map[string]interface{}{}
+	for _, f := range ring.Entries()[2].Fields() {
+		fields[f.Key] = f.Value
+	}
+	if fields["run_id"] != run.ID() || fields["attempt"] != int64(2) || fields["runs"] != int64(1) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	s := l.JobSummary("export")
+	if s.Runs != 2 || s.Succeeded != 1 || s.Failed != 1 || s.ConsecutiveFailures != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+func TestLogger_Entries(t *testing.T) {
+	l := logger.New(&logger.Config{Output: io.Discard, CacheSize: 3})
+	for i := 0; i < 5; i++ {
+		l.Debug("entry", i)
+	}
+	l.Error("failed")
+
+	entries := l.Entries()
+	if len(entries) != 3 || entries[0].Message() != "entry3" || entries[2].Level() != logger.LevelError {
+		t.Fatalf("the cache should hold the last 3 entries of every level: %v", entries)
+	}
+
+	var buf bytes.Buffer
+	if err := l.Flush(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 3 || !strings.Contains(buf.String(), "failed") {
+		t.Errorf("unexpected flush output: %q", buf.String())
+	}
+	if len(l.Entries()) != 0 {
+		t.Error("Flush should empty the cache")
+	}
+
+	l = logger.New(&logger.Config{Output: io.Discard, CacheTTL: 10 * time.Millisecond})
+	l.Info("old")
+	time.Sleep(20 * time.Millisecond)
+	l.Info("new")
+	if entries := l.Entries(); len(entries) != 1 || entries[0].Message() != "new" {
+		t.Errorf("entries older than the TTL should be evicted: %v", entries)
+	}
+}
+
+func TestLogger_Infof(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Level: logger.LevelInfo})
+	l.Infof("processed %d of %s", 3, "jobs")
+	l.Debugf("hidden %d", 1)
+	l.WarnCf("retry in %v", time.Second)
+
+	entries := ring.Entries()
+	if len(entries) != 2 || entries[0].Message() != "processed 3 of jobs" || entries[1].Message() != "retry in 1s" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+	if entries[0].Caller().Function != "" || !strings.Contains(entries[1].Caller().Function, "TestLogger_Infof") {
+		t.Errorf("only the C variants should record the caller: %+v, %+v", entries[0].Caller(), entries[1].Caller())
+	}
+}
+
+func TestLogger_GoRestartBackoff(t *testing.T) {
+	l := logger.New(&logger.Config{Output: io.Discard})
+	var runs atomic.Int32
+	done := make(chan struct{})
+	start := time.Now()
+	l.Go(func() {
+		if runs.Add(1) == 3 {
+			close(done)
+		}
+		panic("boom")
+	}, logger.Restart(2, 0))
+
+	<-done
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("restarted after %v, want a growing minimum backoff", elapsed)
+	}
+}
+
+func TestLogger_Event(t *testing.T) {
+	ring := logger.NewRing(10)
+	forwarded := logger.NewRing(10)
+	events := &logger.Events{Interval: 20 * time.Millisecond, Sink: forwarded}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Events: events})
+
+	l.With(logger.Fields{"tenant": "acme"}).Named("web").Event("export_clicked", logger.Fields{"format": "csv"})
+	l.Event("export_clicked")
+	l.Event("signup")
+	time.Sleep(50 * time.Millisecond)
+	l.Close()
+
+	if c := events.Counts(); c["export_clicked"] != 2 || c["signup"] != 1 {
+		t.Errorf("unexpected counts: %v", c)
+	}
+	if forwarded.Len() != 3 {
+		t.Errorf("got %d forwarded events, want 3", forwarded.Len())
+	}
+	if e := forwarded.Entries()[0]; e.Name() != "web" || fmt.Sprint(e.Fields()) != "[{tenant acme} {event export_clicked} {format csv}]" {
+		t.Errorf("the forwarded event should keep the logger context: %s %v", e.Name(), e.Fields())
+	}
+	var summary *logger.Entry
+	for _, e := range ring.Entries() {
+		if e.Message() == "event counts" {
+			summary = &e
+			break
+		}
+	}
+	if summary == nil {
+		t.Fatal("no event counts entry")
+	}
+	fields := map[string]interface{}{}
+	for _, f := range summary.Fields() {
+		fields[f.Key] = f.Value
+	}
+	if fields["export_clicked"] != int64(2) || fields["signup"] != int64(1) {
+		t.Errorf("unexpected event counts: %v", fields)
+	}
+}
+
+func TestLogger_Panic(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	defer func() {
+		if r := recover(); r != "index 3 out of range" {
+			t.Errorf("got panic %v", r)
+		}
+		entries := ring.Entries()
+		if len(entries) != 1 || entries[0].Level() != logger.LevelPanic {
+			t.Errorf("unexpected entries: %v", entries)
+		}
+	}()
+	l.Panicf("index %d out of range", 3)
+}
+
+func TestLogger_Recover(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("the panic should continue, got %v", r)
+		}
+		entries := ring.Entries()
+		if len(entries) != 1 || entries[0].Level() != logger.LevelFatal {
+			t.Fatalf("unexpected entries: %v", entries)
+		}
+		fields := entries[0].Fields()
+		if fields[0].Value != "boom" || !strings.Contains(fmt.Sprint(fields[1].Value), "TestLogger_Recover") {
+			t.Errorf("unexpected fields: %v", fields)
+		}
+	}()
+	defer l.Recover()
+	panic("boom")
+}
+
+func TestLogger_CrashReports(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crash.txt")
+	report := "panic: runtime error: index out of range [3] with length 3\n\ngoroutine 7 [running]:\nmain.worker()\n\t/app/main.go:42 +0x1d\n"
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	if err := l.CrashReports(path); err != nil {
+		t.Fatal(err)
+	}
+	defer debug.SetCrashOutput(nil, debug.CrashOptions{})
+
+	entries := ring.Entries()
+	if len(entries) != 1 || entries[0].Message() != "previous run crashed" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+	fields := entries[0].Fields()
+	if fields[0].Value != "runtime error: index out of range [3] with length 3" || !strings.HasPrefix(fmt.Sprint(fields[1].Value), "goroutine 7") {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("the report should be removed, got %q", data)
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	sl, err := logger.NewSyslog(logger.SyslogOptions{Network: "udp", Addr: pc.LocalAddr().String(), AppName: "orders", Hostname: "web1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sl.Close()
+
+	e := logger.NewEntry(time.Date(2025, 1, 9, 10, 11, 12, 0, time.UTC), logger.LevelWarn, "slow query",
+		logger.Field{Key: "table", Value: `a"b]`}, logger.Field{Key: "user", Value: []logger.Field{{Key: "id", Value: 7}}})
+	if err := sl.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf(`<12>1 2025-01-09T10:11:12Z web1 orders %d - [fields@32473 table="a\"b\]" user.id="7"] slow query`, os.Getpid())
+	if got := string(buf[:n]); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestLogger_VolumeHistogram(t *testing.T) {
+	l := logger.New(&logger.Config{Output: io.Discard})
+	for i := 0; i < 3; i++ {
+		l.Info("request")
+	}
+	l.Tag("db").Error("timeout")
+
+	buckets := l.VolumeHistogram(time.Minute, 6)
+	if len(buckets) != 6 {
+		t.Fatalf("got %d buckets, want 6", len(buckets))
+	}
+	last := buckets[5]
+	if last.Counts[logger.LevelInfo] != 3 || last.Counts[logger.LevelError] != 1 || last.Total() != 4 {
+		t.Errorf("unexpected last bucket: %+v", last)
+	}
+	if buckets[0].Total() != 0 || buckets[0].End.Sub(buckets[0].Start) != 10*time.Second {
+		t.Errorf("unexpected first bucket: %+v", buckets[0])
+	}
+	if n := len(l.VolumeHistogram(time.Hour, 60)); n != 60 {
+		t.Errorf("got %d minute buckets, want 60", n)
+	}
+}
+
+func TestLogger_CloseHeatMap(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, HeatMap: true})
+	l.Info("started")
+	l.Info("working")
+	l.Warn("slow")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := ring.Entries()
+	summary := entries[len(entries)-1]
+	if summary.Message() != "log summary" {
+		t.Fatalf("got %q, want the summary last", summary.Message())
+	}
+	rows := map[string]map[string]interface{}{}
+	for _, f := range summary.Fields() {
+		if nested, ok := f.Value.([]logger.Field); ok {
+			rows[f.Key] = map[string]interface{}{}
+			for _, n := range nested {
+				rows[f.Key][n.Key] = n.Value
+			}
+		}
+	}
+	if len(rows) != 2 || rows["info"]["total"] != int64(2) || rows["warn"]["total"] != int64(1) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if m := rows["info"]["map"].(string); !strings.HasSuffix(m, "█") && !strings.HasSuffix(m, "@") {
+		t.Errorf("the current minute should be the busiest: %q", m)
+	}
+}
+
+type requestIDKey struct{}
+
+func TestLogger_ContextHook(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	reqLog := l.WithFields(logger.Fields{"service": "api"})
+	l.AddContextHook(func(ctx context.Context) []logger.Field {
+		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+			return []logger.Field{{Key: "request_id", Value: id}}
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "7f3a")
+	reqLog.InfoCtx(ctx, "handled", logger.Field{Key: "status", Value: 200})
+	l.ErrorCtx(context.Background(), "failed")
+
+	entries := ring.Entries()
+	var keys []string
+	for _, f := range entries[0].Fields() {
+		keys = append(keys, fmt.Sprintf("%s=%v", f.Key, f.Value))
+	}
+	if got := strings.Join(keys, " "); got != "service=api request_id=7f3a status=200" {
+		t.Errorf("got fields %q", got)
+	}
+	if len(entries[1].Fields()) != 0 || entries[1].Caller().Line == 0 {
+		t.Errorf("unexpected error entry: %+v", entries[1])
+	}
+}
+
+type ntpClock struct{ calls atomic.Int32 }
+
+func (c *ntpClock) Now() time.Time {
+	c.calls.Add(1)
+	return time.Now()
+}
+
+func (c *ntpClock) Offset() time.Duration { return 3 * time.Millisecond }
+
+func TestSetClock(t *testing.T) {
+	c := &ntpClock{}
+	logger.SetClock(c)
+	t.Cleanup(func() { logger.SetClock(nil) })
+
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	l.Info("audited")
+
+	if c.calls.Load() != 1 {
+		t.Errorf("the clock was called %d times, want 1", c.calls.Load())
+	}
+	fields := ring.Entries()[0].Fields()
+	if len(fields) != 1 || fields[0].Key != "clock_offset" || fields[0].Value != 3*time.Millisecond {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestSetClock_Before1970(t *testing.T) {
+	for _, at := range []time.Time{time.Date(1969, 12, 31, 23, 59, 30, 0, time.UTC), {}} {
+		logger.SetClock(logger.ClockFunc(func() time.Time { return at }))
+		l := logger.New(&logger.Config{Output: io.Discard})
+		l.Error("skewed")
+		if h := l.VolumeHistogram(time.Minute, 1); len(h) != 1 {
+			t.Errorf("got %d buckets", len(h))
+		}
+	}
+	logger.SetClock(nil)
+}
+
+func TestSetClock_SteppedBack(t *testing.T) {
+	now := time.Now().Add(-24 * time.Hour)
+	logger.SetClock(logger.ClockFunc(func() time.Time { return now }))
+	t.Cleanup(func() { logger.SetClock(nil) })
+
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	l.Info("before")
+	now = now.Add(-time.Hour)
+	l.Info("stepped")
+	now = now.Add(time.Second)
+	l.Info("after")
+
+	var got []string
+	for _, e := range ring.Entries() {
+		got = append(got, fmt.Sprint(e.Message(), e.Fields()))
+	}
+	want := "before[],clock went backwards[{skew 1h0m0s}],stepped[{clock_skew 1h0m0s}],after[]"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %v", got)
+	}
+}
+
+type hangingSink struct{ release chan struct{} }
+
+func (s hangingSink) WriteEntry(e logger.Entry) error {
+	<-s.release
+	return nil
+}
+
+type failingSink struct{}
+
+func (failingSink) WriteEntry(e logger.Entry) error { return errors.New("disk full") }
+
+func TestLogger_AsyncDropWhenFull(t *testing.T) {
+	hang := hangingSink{release: make(chan struct{})}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{hang}, Async: &logger.Async{BufferSize: 1, DropWhenFull: true}})
+	for i := 0; i < 5; i++ {
+		l.Info("entry")
+	}
+	if got := l.Dropped(); got < 3 {
+		t.Errorf("a full queue should drop entries instead of blocking, dropped %d", got)
+	}
+	close(hang.release)
+	l.Close()
+}
+
+func TestLogger_AsyncSyncFromHook(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Async: &logger.Async{BufferSize: 1}})
+	l.AddHook(logger.HookFunc(func(e logger.Entry) error {
+		for i := 0; i < 3; i++ {
+			l.Info("logged by the hook")
+		}
+		return l.Sync()
+	}), logger.LevelError)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Error("request failed")
+		l.Sync()
+		l.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sync from a hook deadlocked the Async writer")
+	}
+	if ring.Len() != 4 {
+		t.Errorf("got %d entries, want 4", ring.Len())
+	}
+}
+
+func TestLogger_AsyncSyncDuringHook(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Async: &logger.Async{}})
+	defer l.Close()
+	running, release := make(chan struct{}), make(chan struct{})
+	l.AddHook(logger.HookFunc(func(e logger.Entry) error {
+		close(running)
+		<-release
+		return nil
+	}), logger.LevelError)
+
+	l.Error("request failed")
+	l.Info("retrying")
+	<-running
+	synced := make(chan struct{})
+	go func() {
+		l.Sync()
+		close(synced)
+	}()
+	select {
+	case <-synced:
+		t.Fatal("Sync from another goroutine returned while the hook was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-synced
+	if ring.Len() != 2 {
+		t.Errorf("got %d entries after Sync, want 2", ring.Len())
+	}
+}
+
+func TestLogger_Lint(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, Lint: &logger.Lint{}})
+	l.Info("Connected to db.")
+	l.Info("Connected to db.")
+	l.Info("HTTP server listening")
+	l.Info("loading...")
+	l.Info("user %s not found", "bob")
+	l.Info("GET /a%20file took 3ms")
+	l.Infof("disk at %s", "95%d")
+
+	var got []string
+	for _, e := range ring.Entries() {
+		if e.Message() == "log message lint" {
+			got = append(got, fmt.Sprint(e.Fields()[0].Value))
+		}
+	}
+	want := "message ends with punctuation,message starts with a capital letter," +
+		`message contains the format verb "%s", use the f variant such as Infof`
+	if strings.Join(got, ",") != want {
+		t.Errorf("got lint warnings %v, want each problem reported once", got)
+	}
+}
+
+func TestLogger_Every(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	for i := 0; i < 100; i++ {
+		l.Every(20 * time.Millisecond).Warn("retrying connect")
+	}
+	l.Every(20 * time.Millisecond).Warn("other")
+	if ring.Len() != 2 {
+		t.Fatalf("repeats within the interval should be suppressed, got %d entries", ring.Len())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for ring.Len() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("want a summary for the repeated entry only, got %d entries", len(entries))
+	}
+	summary := entries[2]
+	if summary.Message() != "retrying connect" || summary.Fields()[0] != (logger.Field{Key: "repeated", Value: 99}) {
+		t.Errorf("unexpected summary: %s %v", summary.Message(), summary.Fields())
+	}
+}
+
+func TestLogger_Template(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, Template: `{{.Level}} {{.Time.Format "15:04"}} {{.Msg}} {{.Fields}}`})
+	l.Info("ready", logger.Field{Key: "port", Value: 80})
+	if want := " ready port=80\n"; !strings.HasPrefix(out.String(), "info ") || !strings.HasSuffix(out.String(), want) {
+		t.Errorf("got %q", out.String())
+	}
+
+	out.Reset()
+	l = logger.New(&logger.Config{Output: &out, Template: "{{.Msg"})
+	l.Info("still logged")
+	if !strings.Contains(out.String(), "invalid Config.Template") || !strings.Contains(out.String(), "still logged") {
+		t.Errorf("an invalid template should be reported and fall back to the default layout: %q", out.String())
+	}
+
+	out.Reset()
+	l = logger.New(&logger.Config{Output: &out, Formatter: logger.FormatterFunc(func(e logger.Entry) []byte {
+		return []byte(e.Level().String() + "|" + e.Message())
+	})})
+	l.Warn("disk almost full")
+	if out.String() != "warn|disk almost full\n" {
+		t.Errorf("got %q", out.String())
+	}
+}
+
+func TestHijackStdlib(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	restore := logger.HijackStdlib(l, logger.LevelInfo)
+	defer restore()
+	log.SetPrefix("[db] ")
+	log.Printf("connected to %s", "orders")
+	log.Print("ERROR: connection lost")
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Name() != "db" || e.Level() != logger.LevelInfo || e.Message() != "connected to orders" {
+		t.Errorf("unexpected entry: %s %s %q", e.Name(), e.Level(), e.Message())
+	}
+	if e := entries[1]; e.Level() != logger.LevelError || e.Message() != "connection lost" {
+		t.Errorf("the level should be taken from the message: %s %q", e.Level(), e.Message())
+	}
+	if fn := entries[0].Caller().Function; !strings.HasSuffix(fn, "TestHijackStdlib") {
+		t.Errorf("the caller should be the function calling log, got %s", fn)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Level: logger.LevelInfo, Sinks: []logger.Sink{ring}})
+	logger.SetDefault(l)
+	defer logger.SetDefault(nil)
+
+	logger.Debug("skipped")
+	logger.Alert("disk almost full")
+	logger.SetLevel(logger.LevelDebug)
+	logger.Debugf("retry %d", 2)
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Level() != logger.LevelAlert || e.Message() != "disk almost full" {
+		t.Errorf("unexpected entry: %s %q", e.Level(), e.Message())
+	}
+	if fn := entries[0].Caller().Function; !strings.HasSuffix(fn, "TestSetDefault") {
+		t.Errorf("the caller should be the function calling Alert, got %s", fn)
+	}
+	if e := entries[1]; e.Message() != "retry 2" {
+		t.Errorf("unexpected message %q", e.Message())
+	}
+	if !logger.Enabled(logger.LevelDebug) || l.GetLevel() != logger.LevelDebug {
+		t.Error("SetLevel should change the level of the default logger")
+	}
+}
+
+func TestLogger_CaptureStdio(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	stop, err := l.CaptureStdio(logger.CaptureOptions{Stdout: true, Stderr: true})
+	if err != nil {
+		t.Skip(err)
+	}
+	fmt.Println("printed by a dependency")
+	fmt.Fprintln(os.Stderr, "warning from a C library")
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, e := range ring.Entries() {
+		got[strings.Join(e.Tags(), ",")] = e.Level().String() + ":" + e.Message()
+	}
+	if got["stdout"] != "info:printed by a dependency" || got["stderr"] != "warn:warning from a C library" {
+		t.Errorf("unexpected captured entries: %v", got)
+	}
+}
+
+func TestLogger_CaptureStdio_LongLine(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	stop, err := l.CaptureStdio(logger.CaptureOptions{Stdout: true})
+	if err != nil {
+		t.Skip(err)
+	}
+	fmt.Println(strings.Repeat("x", 100<<10))
+	fmt.Println("still captured")
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 3 || len(entries[0].Message()) != 64<<10 || entries[2].Message() != "still captured" {
+		t.Errorf("got %d entries", len(entries))
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	ring := logger.NewRing(10)
+	hang := hangingSink{release: make(chan struct{})}
+	defer close(hang.release)
+	m := logger.MultiSinkOptions(logger.MultiOptions{Timeout: 20 * time.Millisecond, QueueSize: 2}, hang, failingSink{}, ring)
+
+	if err := m.WriteEntry(logger.NewEntry(time.Now(), logger.LevelInfo, "first")); err != nil {
+		t.Errorf("a write should only queue the entry: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond) // the hanging sink times out on the first entry
+	for i := 0; i < 4; i++ {
+		m.WriteEntry(logger.NewEntry(time.Now(), logger.LevelInfo, "next"))
+	}
+	err := m.Flush()
+	if err == nil || !strings.Contains(err.Error(), "multi sink 0: timed out") || !strings.Contains(err.Error(), "multi sink 1: disk full") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ring.Len() != 5 || m.Dropped()[0] < 2 || m.Dropped()[2] != 0 {
+		t.Errorf("got %d entries and %v dropped", ring.Len(), m.Dropped())
+	}
+}
+
+func TestMultiSink_Concurrent(t *testing.T) {
+	ring := logger.NewRing(40000)
+	m := logger.MultiSink(ring)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				m.WriteEntry(logger.NewEntry(time.Now(), logger.LevelInfo, "entry"))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ring.Len() != 40000 || m.Dropped()[0] != 0 {
+		t.Errorf("got %d entries and %v dropped", ring.Len(), m.Dropped())
+	}
+}
+
+func TestLogger_CallerLevel(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}, CallerLevel: logger.LevelWarn})
+	l.Debug("hot path")
+	l.WarnC("slow")
+	l.Error("failed")
+
+	entries := ring.Entries()
+	if !entries[0].Caller().IsZero() {
+		t.Errorf("Debug below CallerLevel should skip the caller: %+v", entries[0].Caller())
+	}
+	for _, e := range entries[1:] {
+		if !strings.Contains(e.Caller().Function, "TestLogger_CallerLevel") {
+			t.Errorf("%s should keep the caller: %+v", e.Level(), e.Caller())
+		}
+	}
+}
+
+func TestLogger_StackTraceLevel(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out, StackTraceLevel: logger.LevelError, StackTraceDepth: 2})
+	l.Warn("slow")
+	l.Error("failed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if strings.Contains(lines[0], "stack") || len(lines) != 7 {
+		t.Fatalf("want a two frame stack below the Error entry only, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[3], "TestLogger_StackTraceLevel") || !strings.Contains(lines[4], "logger_test.go:") {
+		t.Errorf("the stack should start at the logging call:\n%s", out.String())
+	}
+}
+
+func TestLogger_Dump(t *testing.T) {
+	type address struct{ City string }
+	type user struct {
+		Name    string
+		Roles   []string
+		Limits  map[string]int
+		Address *address
+	}
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out})
+	l.Dump(user{Name: "ada", Roles: []string{"admin"}, Limits: map[string]int{"b": 2, "a": 1}})
+
+	want := `    logger_test.user{
+      Name: "ada",
+      Roles: []string{
+        "admin",
+      },
+      Limits: map[string]int{
+        "a": 1,
+        "b": 2,
+      },
+      Address: (*logger_test.address)(nil),
+    }`
+	if !strings.Contains(out.String(), "↳ logger_test.user\n"+want) {
+		t.Errorf("unexpected dump:\n%s", out.String())
+	}
+
+	out.Reset()
+	quiet := logger.New(&logger.Config{Output: &out, Level: logger.LevelInfo})
+	quiet.Dump(user{})
+	if out.Len() != 0 {
+		t.Errorf("Dump should honor the level: %s", out.String())
+	}
+}
+
+func TestLogger_AddHook(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out})
+	var fired []string
+	l.AddHook(logger.HookFunc(func(e logger.Entry) error {
+		if out.Len() == 0 {
+			t.Error("hooks should run after the console output")
+		}
+		fired = append(fired, e.Message())
+		return nil
+	}), logger.LevelError, logger.LevelAlert)
+
+	l.Info("ok")
+	l.Tag("db").Error("failed")
+	if strings.Join(fired, ",") != "failed" {
+		t.Errorf("got %v, want only the error", fired)
+	}
+}
+
+func TestLogger_ErrorErr(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out})
+	err := fmt.Errorf("read app.yaml: %w", fmt.Errorf("open app.yaml: %w", os.ErrNotExist))
+	l.ErrorErr(err, "loading config")
+	l.ErrorErr(os.ErrNotExist)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "TestLogger_ErrorErr") {
+		t.Fatalf("the caller should be printed: %q", out.String())
+	}
+	want := `↳ loading config error="read app.yaml: open app.yaml: file does not exist" chain.0="read app.yaml" chain.1="open app.yaml" chain.2="file does not exist"`
+	if lines[1] != want {
+		t.Errorf("got %q, want %q", lines[1], want)
+	}
+	if lines[3] != "↳ file does not exist" {
+		t.Errorf("got %q, want the error as message", lines[3])
+	}
+}
+
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) Callers() []uintptr { return e.pcs }
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 8)
+	return &stackError{msg: msg, pcs: pcs[:runtime.Callers(1, pcs)]}
+}
+
+func TestLogger_ErrorErr_Join(t *testing.T) {
+	var out bytes.Buffer
+	l := logger.New(&logger.Config{Output: &out})
+	err := fmt.Errorf("import: %w", errors.Join(
+		errors.New("row 3: bad date"),
+		fmt.Errorf("row 7: %w", newStackError("duplicate key")),
+	))
+	l.ErrorErr(err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := `↳ import errors.0="row 3: bad date" errors.1.error="row 7: duplicate key" errors.1.chain.0="row 7" errors.1.chain.1="duplicate key"`
+	if len(lines) < 5 || lines[1] != want {
+		t.Fatalf("got:\n%s\nwant %s", out.String(), want)
+	}
+	if lines[2] != "  errors.1.stack:" || !strings.Contains(lines[3], "newStackError") {
+		t.Errorf("the stack of the second error should follow:\n%s", out.String())
+	}
+}
@@ -0,0 +1,103 @@
+// Package logtest provides helpers for asserting on log output in tests.
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+// update is namespaced so it cannot clash with an -update flag of the test
+// package, which is honored as well.
+var update = flag.Bool("logtest.update", false, "update golden files checked by logtest.AssertGolden")
+
+func updating() bool {
+	if *update {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			b, _ := g.Get().(bool)
+			return b
+		}
+	}
+	return false
+}
+
+var normalizers = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`), "<time>"},
+	{regexp.MustCompile(`\d{4}/\d{2}/\d{2}`), "<date>"},
+	{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}\b`), "<clock>"},
+	{regexp.MustCompile(`\(([^()\s]+):\d+\)`), "($1:<line>)"},
+	{regexp.MustCompile(`"file":"[^"]*"`), `"file":"<file>"`},
+	{regexp.MustCompile(`"line":\d+`), `"line":0`},
+}
+
+// Normalize strips color codes and replaces timestamps, file paths and line
+// numbers in console or JSON log output with stable placeholders.
+func Normalize(b []byte) []byte {
+	b = []byte(logger.StripANSI(string(b)))
+	for _, n := range normalizers {
+		b = n.re.ReplaceAll(b, []byte(n.repl))
+	}
+	return b
+}
+
+// AssertGolden compares the normalized log output with the golden file at
+// path. Run the test with -logtest.update, or with an -update flag the test
+// package defines, to write the file instead.
+func AssertGolden(t testing.TB, recorded []byte, path string) {
+	t.Helper()
+	got := Normalize(recorded)
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("logtest: creating golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("logtest: writing golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("logtest: reading golden file: %v (run with -logtest.update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("logtest: log output differs from %s (-want +got):\n%s", path, diffLines(string(want), string(got)))
+	}
+}
+
+func diffLines(want, got string) string {
+	wl := strings.Split(want, "\n")
+	gl := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w == g {
+			continue
+		}
+		if i < len(wl) {
+			fmt.Fprintf(&b, "%4d - %s\n", i+1, w)
+		}
+		if i < len(gl) {
+			fmt.Fprintf(&b, "%4d + %s\n", i+1, g)
+		}
+	}
+	return b.String()
+}
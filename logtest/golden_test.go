@@ -0,0 +1,45 @@
+package logtest
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+func TestNormalize(t *testing.T) {
+	in := "[\x1b[1m INFO \x1b[0m] 2025/01/09 10:11:12 (main.run:42)\n" +
+		`{"v":1,"time":"2025-01-09T10:11:12.123+01:00","caller":{"file":"/src/main.go","line":42}}`
+	want := "[ INFO ] <date> <clock> (main.run:<line>)\n" +
+		`{"v":1,"time":"<time>","caller":{"file":"<file>","line":0}}`
+	if got := string(Normalize([]byte(in))); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// Defined like test packages with golden files of their own do.
+var pkgUpdate = flag.Bool("update", false, "update golden files")
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "expected.log")
+	record := func() []byte {
+		var out bytes.Buffer
+		l := logger.New(&logger.Config{Output: &out})
+		l.Info("started")
+		l.WarnC("disk almost full")
+		return out.Bytes()
+	}
+
+	*update = true
+	AssertGolden(t, record(), path)
+	*update = false
+	AssertGolden(t, record(), path)
+
+	other := filepath.Join(t.TempDir(), "other.log")
+	*pkgUpdate = true
+	AssertGolden(t, record(), other)
+	*pkgUpdate = false
+	AssertGolden(t, record(), other)
+}
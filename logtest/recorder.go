@@ -0,0 +1,118 @@
+package logtest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+// Recorder is a sink keeping every entry it receives, to assert that a code
+// path logged what it should.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []logger.Entry
+}
+
+// NewRecorder returns a logger recording its entries of all levels, without
+// writing any output, and its recorder:
+//
+//	log, rec := logtest.NewRecorder()
+//	s := NewServer(log)
+//	s.Reload()
+//	rec.AssertMessage(t, logger.LevelWarn, "config unchanged")
+func NewRecorder() (*logger.Logger, *Recorder) {
+	r := &Recorder{}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{r}})
+	return l, r
+}
+
+func (r *Recorder) WriteEntry(e logger.Entry) error {
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.mu.Unlock()
+	return nil
+}
+
+// Entries returns the recorded entries, oldest first.
+func (r *Recorder) Entries() []logger.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]logger.Entry(nil), r.entries...)
+}
+
+// LastEntry returns the latest entry, or false when none was recorded.
+func (r *Recorder) LastEntry() (logger.Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return logger.Entry{}, false
+	}
+	return r.entries[len(r.entries)-1], true
+}
+
+// ContainsMessage reports whether an entry of the given level with substr
+// in its message was recorded.
+func (r *Recorder) ContainsMessage(level logger.Level, substr string) bool {
+	return r.Count(level, substr) > 0
+}
+
+// Count returns the number of recorded entries of the given level with
+// substr in their message.
+func (r *Recorder) Count(level logger.Level, substr string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, e := range r.entries {
+		if e.Level() == level && strings.Contains(e.Message(), substr) {
+			n++
+		}
+	}
+	return n
+}
+
+// Query returns the recorded entries matching a filter expression, see
+// logger.CompileFilter.
+func (r *Recorder) Query(expr string) ([]logger.Entry, error) {
+	return logger.Query(logger.EntrySlice(r.Entries()), expr).Entries()
+}
+
+// Reset drops the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
+
+// AssertMessage fails the test unless an entry of the given level with
+// substr in its message was recorded, listing the recorded entries.
+func (r *Recorder) AssertMessage(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if !r.ContainsMessage(level, substr) {
+		t.Errorf("logtest: no %s entry containing %q was logged%s", level, substr, r.listing())
+	}
+}
+
+// AssertNoMessage fails the test if an entry of the given level with substr
+// in its message was recorded.
+func (r *Recorder) AssertNoMessage(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if r.ContainsMessage(level, substr) {
+		t.Errorf("logtest: unexpected %s entry containing %q%s", level, substr, r.listing())
+	}
+}
+
+func (r *Recorder) listing() string {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return ", nothing was logged"
+	}
+	var b strings.Builder
+	b.WriteString(", got:")
+	for _, e := range entries {
+		b.WriteString("\n\t" + e.PlainString())
+	}
+	return b.String()
+}
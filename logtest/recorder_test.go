@@ -0,0 +1,46 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/pecet3/logger"
+)
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fakeT) Helper()                                   {}
+func (t *fakeT) Errorf(format string, args ...interface{}) { t.failed = true }
+
+func TestRecorder(t *testing.T) {
+	log, rec := NewRecorder()
+	log.Debug("cache miss")
+	log.Warn("config unchanged, skipping reload")
+	log.With(logger.Field{Key: "tenant", Value: "acme"}).Error("reload failed")
+
+	if n := len(rec.Entries()); n != 3 {
+		t.Fatalf("got %d entries, want 3", n)
+	}
+	if e, ok := rec.LastEntry(); !ok || e.Message() != "reload failed" {
+		t.Errorf("unexpected last entry %q", e.Message())
+	}
+	if !rec.ContainsMessage(logger.LevelWarn, "unchanged") || rec.ContainsMessage(logger.LevelInfo, "unchanged") {
+		t.Error("ContainsMessage should match the level and the message")
+	}
+	if got, err := rec.Query(`fields.tenant == "acme"`); err != nil || len(got) != 1 {
+		t.Errorf("got %d entries, err %v", len(got), err)
+	}
+	rec.AssertMessage(t, logger.LevelDebug, "cache miss")
+
+	ft := &fakeT{}
+	rec.AssertMessage(ft, logger.LevelError, "timeout")
+	if !ft.failed {
+		t.Error("AssertMessage should fail without a matching entry")
+	}
+	rec.Reset()
+	if _, ok := rec.LastEntry(); ok {
+		t.Error("Reset should drop the entries")
+	}
+}
@@ -3,8 +3,8 @@ package logger
 import (
 	"context"
 	"fmt"
-	"runtime"
-	"strconv"
+	"io"
+	"os"
 	"sync"
 	"time"
 )
@@ -21,29 +21,157 @@ type Email struct {
 }
 
 type Config struct {
+	// Name is attached to every entry, e.g. the component or test name.
+	Name        string
 	IsDebugMode bool
 	Email       *Email
 	Duration    time.Duration
+	Sinks       []Sink
+	// Level is the lowest level that gets logged.
+	Level Level
+	// AtomicLevel, when set, is used instead of Level, so several loggers
+	// can share a level changed at runtime.
+	AtomicLevel *AtomicLevel
+	// Filter, when set, drops every entry it does not match.
+	Filter *Filter
+	// Sampler, when set, thins out Info and Debug entries while the error
+	// rate is low.
+	Sampler *Sampler
+	// Routes send matching entries to additional sinks, see Route.
+	Routes []Route
+	Format Format
+	// Output is where entries are written, os.Stdout by default.
+	Output io.Writer
+	// ErrorOutput, when set, receives Error and Alert entries instead of
+	// Output, e.g. os.Stderr.
+	ErrorOutput io.Writer
+	// Color chooses when the console output is styled. By default it is
+	// only styled for terminals, and not when NO_COLOR is set.
+	Color ColorMode
+	// Badges overrides the console label of a level, e.g. " INF " or "🔥".
+	Badges map[Level]string
+	// BadgeWidth pads or cuts every badge to the given number of terminal
+	// cells, keeping wide characters and emoji whole.
+	BadgeWidth int
+	// Markers overrides the "↳" starting the message line of caller-aware
+	// entries of a level; an empty marker leaves it out.
+	Markers map[Level]string
+	// Inline prints the message of caller-aware entries of the given levels
+	// on the header line, after the caller, instead of on a line of its own.
+	Inline map[Level]bool
+	// SingleLine prints the message of every caller-aware entry on the
+	// header line, as Inline does for single levels.
+	SingleLine bool
+	// Icons prefixes console entries with per-level icons and softer colors.
+	// ASCII symbols are used when the locale is not UTF-8.
+	Icons bool
+	// Theme styles the console output, DefaultTheme when nil.
+	Theme *Theme
+	// Uptime adds a column with the seconds elapsed since process start.
+	Uptime Uptime
+	// CacheSize is the number of recent entries kept for Entries, Flush and
+	// the reports of the senders, 1000 by default.
+	CacheSize int
+	// CacheTTL, when set, evicts cached entries older than it.
+	CacheTTL time.Duration
+	// Events, when set, counts the entries logged with Event.
+	Events *Events
+	// Async, when set, writes entries from a background goroutine.
+	Async *Async
+	// Formatter, when set, renders console entries instead of the default
+	// layout.
+	Formatter Formatter
+	// Template is a text/template for the console layout, executed with
+	// TemplateData, e.g. "{{.Badge}} {{.Time}} {{.Caller}} {{.Msg}}".
+	// Formatter takes precedence.
+	Template string
+	// Lint, when set, warns about messages breaking its style rules. Meant
+	// for development only.
+	Lint *Lint
+	// CallerLevel is the lowest level whose entries get the function and
+	// line of the call, saving the cost of runtime.Caller on hot Debug or
+	// Info paths. By default every method capturing the caller does.
+	CallerLevel Level
+	// StackTraceLevel, when above LevelDebug, adds a stack field with the
+	// stack trace of the logging goroutine to the entries of that level and
+	// above, e.g. LevelError.
+	StackTraceLevel Level
+	// StackTraceDepth is the number of frames kept, 32 by default.
+	StackTraceDepth int
+	// StackTraceSkip leaves out that many frames below the logging call,
+	// e.g. those of a wrapper around the logger.
+	StackTraceSkip int
+	// SlowThreshold makes Track log durations of at least it as Warn
+	// entries.
+	SlowThreshold time.Duration
+	// HeatMap makes Close log the number of entries per level and minute
+	// over the lifetime of the process.
+	HeatMap bool
 }
 
+// Logger writes entries to the console and sinks of its Config. The loggers
+// derived from it with WithFields, With, Named, Tag or Group share its
+// configuration, level, sinks, hooks and cache, adding only their own
+// name, tags and fields.
 type Logger struct {
-	cache map[time.Time]string
-	cMu   sync.Mutex
+	cache *entryCache
 
 	senders map[string]Sender
 
-	c *Config
+	c      *Config
+	lvl    *AtomicLevel
+	f      *formatter
+	o      *outputs
+	nop    bool
+	name   string
+	tags   []string
+	fields []Field
+	jobs   *jobStats
+	vol    *volume
+	hooks  *hooks
+	async  *asyncWriter
+	limits *limiters
+	every  *limiter // set by Every
+	skip   int      // set by CallerSkip
+	link   []Field  // group and parent, set by Group
 }
 
 func New(c *Config) *Logger {
 	l := &Logger{
-		cache:   make(map[time.Time]string),
+		cache:   newEntryCache(c.CacheSize, c.CacheTTL),
 		c:       c,
+		name:    c.Name,
+		lvl:     c.AtomicLevel,
+		f:       newFormatter(c, nil),
+		o:       &outputs{},
 		senders: make(map[string]Sender),
+		jobs:    &jobStats{},
+		vol:     &volume{},
+		hooks:   &hooks{},
+		limits:  &limiters{},
 	}
+	if l.lvl == nil {
+		l.lvl = NewAtomicLevel(c.Level)
+	}
+	l.o.set(c, c.Output)
+	l.o.setErrors(c, c.ErrorOutput)
 	if c.Email != nil {
 		l.senders["email"] = c.Email
 	}
+	if c.Async != nil {
+		l.async = newAsyncWriter(*c.Async)
+	}
+	if c.Formatter == nil && c.Template != "" {
+		if _, err := ParseTemplate(c.Template); err != nil {
+			l.Warn("invalid Config.Template, using the default layout: ", err)
+		}
+	}
+	if c.Events != nil {
+		c.Events.start(l)
+	}
+	if c.Duration <= 0 {
+		return l
+	}
 	go func() {
 		for {
 			time.Sleep(c.Duration)
@@ -65,7 +193,7 @@ func New(c *Config) *Logger {
 				}(method)
 			}
 			wg.Wait()
-			l.cleanCache()
+			l.cache.clear()
 			if c.IsDebugMode {
 				debug("cleaned cache")
 			}
@@ -74,24 +202,154 @@ func New(c *Config) *Logger {
 
 	return l
 }
+
+func (l *Logger) enabled(lv Level) bool {
+	return !l.nop && lv >= l.level()
+}
+
+func (l *Logger) level() Level {
+	return l.lvl.Level()
+}
+
+// SetLevel changes the lowest level that gets logged, for l, the loggers
+// derived from it and those sharing its Config.AtomicLevel.
+func (l *Logger) SetLevel(lv Level) {
+	l.lvl.SetLevel(lv)
+}
+
+func (l *Logger) GetLevel() Level {
+	return l.level()
+}
+
+// Enabled reports whether entries of the given level are logged, so callers
+// can skip building expensive values.
+func (l *Logger) Enabled(lv Level) bool {
+	return l.enabled(lv)
+}
+
+func (l *Logger) log(e Entry) {
+	e = l.withContext(e)
+	if e.skew > 0 && l.enabled(LevelWarn) {
+		l.log(makeEntry(LevelWarn, "clock went backwards", []Field{{Key: "skew", Value: e.skew}}, nil))
+	}
+	e.addDynamicFields()
+	if !l.c.Filter.Match(e) {
+		return
+	}
+	if l.c.Sampler != nil && !l.c.Sampler.keep(e) {
+		return
+	}
+	if l.every != nil && !l.every.allow(l, e) {
+		return
+	}
+	if st := l.c.StackTraceLevel; st > LevelDebug && e.level >= st && !e.hasField("stack") {
+		trace := captureStack(l.c.StackTraceSkip+l.skip, l.c.StackTraceDepth)
+		e.fields = append(e.fields[:len(e.fields):len(e.fields)], Field{Key: "stack", Value: trace})
+	}
+	l.output(e)
+	if l.c.Lint != nil {
+		l.lint(e)
+	}
+}
+
+// withContext adds the name, tags and fields of l to e.
+func (l *Logger) withContext(e Entry) Entry {
+	e.name = l.name
+	if len(l.tags) > 0 {
+		e.tags = l.tags
+	}
+	if len(l.fields) > 0 {
+		e.fields = append(l.fields[:len(l.fields):len(l.fields)], e.fields...)
+	}
+	if len(l.link) > 0 {
+		e.fields = append(l.link[:len(l.link):len(l.link)], e.fields...)
+	}
+	return e
+}
+
+// output queues e in Async mode and emits it otherwise.
+func (l *Logger) output(e Entry) {
+	if l.async == nil || !l.async.enqueue(l, e) {
+		l.emit(e, l.writeSinks)
+	}
+}
+
+// emit writes e to the outputs, hooks and sinks, the latter through
+// writeSinks.
+func (l *Logger) emit(e Entry, writeSinks func([]Sink, Entry)) {
+	var routed []*Route
+	exclusive := false
+	for i := range l.c.Routes {
+		r := &l.c.Routes[i]
+		if !r.Filter.Match(e) {
+			continue
+		}
+		routed = append(routed, r)
+		if r.Exclusive {
+			exclusive = true
+			break
+		}
+	}
+	l.vol.add(e)
+	if !exclusive {
+		l.cache.add(e)
+		l.write(e)
+	}
+	l.fireHooks(e)
+	if !exclusive {
+		writeSinks(l.c.Sinks, e)
+	}
+	for _, r := range routed {
+		writeSinks(r.Sinks, e)
+	}
+}
+
+func (l *Logger) writeSinks(sinks []Sink, e Entry) {
+	for _, s := range sinks {
+		if err := s.WriteEntry(e); err != nil && l.c.IsDebugMode {
+			debug("writing entry err: ", err)
+		}
+	}
+}
+
+func (l *Logger) allSinks() []Sink {
+	sinks := l.c.Sinks
+	for _, r := range l.c.Routes {
+		sinks = append(sinks[:len(sinks):len(sinks)], r.Sinks...)
+	}
+	return sinks
+}
+
+func (l *Logger) flushSinks() error {
+	l.async.sync()
+	var first error
+	for _, s := range l.allSinks() {
+		f, ok := s.(interface{ Flush() error })
+		if !ok {
+			continue
+		}
+		err := f.Flush()
+		if err == nil {
+			continue
+		}
+		if l.c.IsDebugMode {
+			debug("flushing sink err: ", err)
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
 func (l *Logger) Alert(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
-
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
-		" ALERT",
-		date,
-		currentTime,
-		fName,
-		strconv.Itoa(line),
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	alert(msg)
+	if !l.enabled(LevelAlert) {
+		return
+	}
+	e := newEntry(LevelAlert, args, l.caller(LevelAlert))
+	msg := e.message
+	l.log(e)
+	l.async.sync()
 
 	wg := sync.WaitGroup{}
 	for _, method := range l.senders {
@@ -114,114 +372,121 @@ func (l *Logger) Alert(args ...interface{}) {
 
 }
 
-func (l *Logger) Error(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
-
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
-		" ERROR",
-		date,
-		currentTime,
-		fName,
-		strconv.Itoa(line),
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	Error(msg)
+// Fatal logs a Fatal entry, flushes the sinks and exits the process with
+// status 1.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.fatal(newEntry(LevelFatal, args, l.caller(LevelFatal)))
 }
 
-func (l *Logger) Info(args ...interface{}) {
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.fatal(makeEntry(LevelFatal, fmt.Sprintf(format, args...), nil, l.caller(LevelFatal)))
+}
+
+func (l *Logger) fatal(e Entry) {
+	if l.enabled(LevelFatal) {
+		l.log(e)
+	}
+	l.flushSinks()
+	os.Exit(1)
+}
 
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s  %s`,
-		" INFO ",
-		date,
-		currentTime,
+// Panic logs a Panic entry and panics with its message.
+func (l *Logger) Panic(args ...interface{}) {
+	l.panic(newEntry(LevelPanic, args, l.caller(LevelPanic)))
+}
 
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	Info(msg)
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	l.panic(makeEntry(LevelPanic, fmt.Sprintf(format, args...), nil, l.caller(LevelPanic)))
 }
 
-func (l *Logger) Warn(args ...interface{}) {
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
+func (l *Logger) panic(e Entry) {
+	if l.enabled(LevelPanic) {
+		l.log(e)
+		l.async.sync()
+	}
+	panic(e.message)
+}
 
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s  %s`,
-		" WARN ",
-		date,
-		currentTime,
+func (l *Logger) Error(args ...interface{}) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.log(newEntry(LevelError, args, l.caller(LevelError)))
+}
+
+func (l *Logger) Info(args ...interface{}) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.log(newEntry(LevelInfo, args, nil))
+}
 
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	Warn(msg)
+func (l *Logger) Warn(args ...interface{}) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.log(newEntry(LevelWarn, args, nil))
 }
 
 func (l *Logger) Debug(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
-
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
-		" DBUG ",
-		date,
-		currentTime,
-		fName,
-		strconv.Itoa(line),
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	Debug(msg)
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.log(newEntry(LevelDebug, args, l.caller(LevelDebug)))
 }
 
 func (l *Logger) InfoC(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
-
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
-		" INFO ",
-		date,
-		currentTime,
-		fName,
-		strconv.Itoa(line),
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	InfoC(msg)
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.log(newEntry(LevelInfo, args, l.caller(LevelInfo)))
 }
 
 func (l *Logger) WarnC(args ...interface{}) {
-	pc, _, line, _ := runtime.Caller(1)
-	fn := runtime.FuncForPC(pc)
-	fName := fn.Name()
-	date := getCurrentDate()
-	currentTime := getCurrentTime()
-
-	msg := fmt.Sprint(args...)
-	contentRaw := fmt.Sprintf(`[%s] %s %s (%s:%s) %s`,
-		" WARN ",
-		date,
-		currentTime,
-		fName,
-		strconv.Itoa(line),
-		msg,
-	)
-	l.addCache(time.Now(), contentRaw)
-	WarnC(msg)
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.log(newEntry(LevelWarn, args, l.caller(LevelWarn)))
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.log(makeEntry(LevelError, fmt.Sprintf(format, args...), nil, l.caller(LevelError)))
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.log(makeEntry(LevelInfo, fmt.Sprintf(format, args...), nil, nil))
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.log(makeEntry(LevelWarn, fmt.Sprintf(format, args...), nil, nil))
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.log(makeEntry(LevelDebug, fmt.Sprintf(format, args...), nil, l.caller(LevelDebug)))
+}
+
+func (l *Logger) InfoCf(format string, args ...interface{}) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.log(makeEntry(LevelInfo, fmt.Sprintf(format, args...), nil, l.caller(LevelInfo)))
+}
+
+func (l *Logger) WarnCf(format string, args ...interface{}) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.log(makeEntry(LevelWarn, fmt.Sprintf(format, args...), nil, l.caller(LevelWarn)))
 }
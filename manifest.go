@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ManifestEntry describes a log file closed by a FileSink. First, Last and
+// Entries cover the entries written by the sink; SHA256 covers the whole
+// file.
+type ManifestEntry struct {
+	File    string    `json:"file"`
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+	Entries int       `json:"entries"`
+	SHA256  string    `json:"sha256"`
+}
+
+// Overlaps reports whether the file holds entries between from and to.
+func (m ManifestEntry) Overlaps(from, to time.Time) bool {
+	return !m.Last.Before(from) && !m.First.After(to)
+}
+
+// Verify checks that the file still matches its checksum.
+func (m ManifestEntry) Verify() error {
+	sum, err := fileSHA256(m.File)
+	if err != nil {
+		return err
+	}
+	if sum != m.SHA256 {
+		return fmt.Errorf("manifest: %s: checksum mismatch", m.File)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func appendManifest(index, path string, first, last time.Time, n int) error {
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	b, err := json.Marshal(ManifestEntry{File: path, First: first, Last: last, Entries: n, SHA256: sum})
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(index, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// ReadManifest reads the index written by a FileSink. Records of a file that
+// was reopened and appended to are merged into one.
+func ReadManifest(path string) ([]ManifestEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []ManifestEntry
+	index := make(map[string]int)
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var m ManifestEntry
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			return nil, fmt.Errorf("manifest: line %d: %w", n, err)
+		}
+		if i, ok := index[m.File]; ok {
+			prev := entries[i]
+			if prev.First.Before(m.First) {
+				m.First = prev.First
+			}
+			m.Entries += prev.Entries
+			entries[i] = m
+			continue
+		}
+		index[m.File] = len(entries)
+		entries = append(entries, m)
+	}
+	return entries, sc.Err()
+}
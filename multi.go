@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type MultiOptions struct {
+	// Timeout is how long a sink may take for a write before it is reported
+	// and counted as dropped, one second by default.
+	Timeout time.Duration
+	// QueueSize is the number of entries queued per sink, 1024 by default.
+	QueueSize int
+}
+
+// Multi is a Sink writing every entry to several sinks. Unlike
+// io.MultiWriter, a sink that fails, panics or hangs never keeps the entry
+// from the others: each sink has a queue and a goroutine of its own, and a
+// write only queues the entry. A full queue makes the write wait up to
+// Timeout; past that, entries are dropped for the sink until its queue has
+// room again. A write taking the sink longer than Timeout is reported, and
+// both are counted by Dropped. Errors of earlier writes are returned by the
+// next call.
+type Multi struct {
+	opts  MultiOptions
+	sinks []*multiTarget
+
+	// stop is closed by Close instead of the queues, so that writes and
+	// waits need no lock against a concurrent Close.
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type multiTarget struct {
+	sink    Sink
+	queue   chan multiOp
+	stop    <-chan struct{}
+	dropped atomic.Int64
+	stalled atomic.Bool // the queue stayed full for Timeout
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// multiOp is a queued write, or a flush or close waited for through done.
+type multiOp struct {
+	entry Entry
+	fn    func(Sink) error
+	done  chan error
+}
+
+func MultiSink(sinks ...Sink) *Multi {
+	return MultiSinkOptions(MultiOptions{}, sinks...)
+}
+
+func MultiSinkOptions(opts MultiOptions, sinks ...Sink) *Multi {
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	m := &Multi{opts: opts, stop: make(chan struct{})}
+	for _, s := range sinks {
+		t := &multiTarget{sink: s, queue: make(chan multiOp, opts.QueueSize), stop: m.stop}
+		m.sinks = append(m.sinks, t)
+		go t.run(opts.Timeout)
+	}
+	return m
+}
+
+func (m *Multi) WriteEntry(e Entry) error {
+	if m.closed() {
+		return errors.New("multi sink: closed")
+	}
+	var errs []error
+	for i, t := range m.sinks {
+		if !t.enqueue(multiOp{entry: e}, m.opts.Timeout) {
+			t.dropped.Add(1)
+			errs = append(errs, fmt.Errorf("multi sink %d: queue full, dropped", i))
+		}
+		errs = append(errs, t.takeErrors(i)...)
+	}
+	return errors.Join(errs...)
+}
+
+// Flush waits for the entries queued before it and flushes the sinks that
+// can be flushed.
+func (m *Multi) Flush() error {
+	return m.wait(func(s Sink) error {
+		if f, ok := s.(interface{ Flush() error }); ok {
+			return f.Flush()
+		}
+		return nil
+	})
+}
+
+// Close waits for the queued entries, closes the sinks implementing
+// io.Closer and stops the goroutines of the sinks.
+func (m *Multi) Close() error {
+	err := m.wait(func(s Sink) error {
+		if c, ok := s.(io.Closer); ok {
+			return c.Close()
+		}
+		return nil
+	})
+	m.closeOnce.Do(func() { close(m.stop) })
+	return err
+}
+
+func (m *Multi) closed() bool {
+	select {
+	case <-m.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dropped returns the number of entries every sink missed because its
+// queue was full or its write timed out, in the order the sinks were given.
+func (m *Multi) Dropped() []int64 {
+	out := make([]int64, len(m.sinks))
+	for i, t := range m.sinks {
+		out[i] = t.dropped.Load()
+	}
+	return out
+}
+
+// wait queues fn behind the entries of every sink and waits for it, giving
+// each queued operation Timeout.
+func (m *Multi) wait(fn func(Sink) error) error {
+	if m.closed() {
+		return nil
+	}
+	done := make([]chan error, len(m.sinks))
+	deadlines := make([]time.Time, len(m.sinks))
+	for i, t := range m.sinks {
+		done[i] = make(chan error, 1)
+		deadlines[i] = time.Now().Add(m.opts.Timeout * time.Duration(len(t.queue)+1))
+		timer := time.NewTimer(time.Until(deadlines[i]))
+		select {
+		case t.queue <- multiOp{fn: fn, done: done[i]}:
+		case <-timer.C:
+			// The queue of a hung sink never has room; report the timeout.
+			done[i] = nil
+		case <-m.stop:
+			timer.Stop()
+			return nil
+		}
+		timer.Stop()
+	}
+
+	var errs []error
+	for i, t := range m.sinks {
+		timer := time.NewTimer(time.Until(deadlines[i]))
+		select {
+		case err := <-done[i]:
+			if err != nil {
+				errs = append(errs, fmt.Errorf("multi sink %d: %w", i, err))
+			}
+		case <-timer.C:
+			errs = append(errs, fmt.Errorf("multi sink %d: timed out", i))
+		case <-m.stop:
+			// Closed concurrently; the operation will not run.
+		}
+		timer.Stop()
+		errs = append(errs, t.takeErrors(i)...)
+	}
+	return errors.Join(errs...)
+}
+
+// enqueue queues op, waiting up to timeout for room unless the queue
+// already stayed full that long.
+func (t *multiTarget) enqueue(op multiOp, timeout time.Duration) bool {
+	select {
+	case t.queue <- op:
+		if t.stalled.Load() {
+			t.stalled.Store(false)
+		}
+		return true
+	default:
+	}
+	if t.stalled.Load() {
+		return false
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case t.queue <- op:
+		return true
+	case <-timer.C:
+		t.stalled.Store(true)
+		return false
+	case <-t.stop:
+		return false
+	}
+}
+
+func (t *multiTarget) run(timeout time.Duration) {
+	for {
+		var op multiOp
+		select {
+		case op = <-t.queue:
+		case <-t.stop:
+			return
+		}
+		var timedOut atomic.Bool
+		watchdog := time.AfterFunc(timeout, func() {
+			timedOut.Store(true)
+			if op.done == nil {
+				t.dropped.Add(1)
+			}
+			t.fail(fmt.Errorf("timed out after %v", timeout))
+		})
+		err := t.call(op)
+		watchdog.Stop()
+		switch {
+		case op.done != nil:
+			op.done <- err
+		case err != nil && !timedOut.Load():
+			t.fail(err)
+		}
+	}
+}
+
+func (t *multiTarget) call(op multiOp) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if op.fn != nil {
+		return op.fn(t.sink)
+	}
+	return t.sink.WriteEntry(op.entry)
+}
+
+// maxMultiErrors caps the errors kept for the next call of a sink.
+const maxMultiErrors = 16
+
+func (t *multiTarget) fail(err error) {
+	t.mu.Lock()
+	if len(t.errs) < maxMultiErrors {
+		t.errs = append(t.errs, err)
+	}
+	t.mu.Unlock()
+}
+
+func (t *multiTarget) takeErrors(i int) []error {
+	t.mu.Lock()
+	errs := t.errs
+	t.errs = nil
+	t.mu.Unlock()
+	for j, err := range errs {
+		errs[j] = fmt.Errorf("multi sink %d: %w", i, err)
+	}
+	return errs
+}
@@ -0,0 +1,30 @@
+package logger
+
+import "io"
+
+// Interface is the small set of methods libraries should accept, so their
+// users can pass a *Logger, Nop() or their own implementation.
+type Interface interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+var _ Interface = (*Logger)(nil)
+
+var nopLogger = &Logger{
+	c:     &Config{},
+	lvl:   NewAtomicLevel(LevelDebug),
+	f:     defaultFormatter,
+	o:     &outputs{w: io.Discard, f: defaultFormatter},
+	jobs:  &jobStats{},
+	hooks: &hooks{},
+	nop:   true,
+}
+
+// Nop returns a logger that discards everything. It is the default a
+// library should use when its user did not provide a logger.
+func Nop() *Logger {
+	return nopLogger
+}
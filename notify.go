@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notifier is a Sink for local development that rings the terminal bell or
+// shows a desktop notification for failures, so they get noticed in long
+// running watch tasks. Desktop notifications use notify-send on Linux and
+// the BSDs, osascript on macOS and PowerShell on Windows. Use it as a
+// pointer:
+//
+//	sinks = append(sinks, &logger.Notifier{Bell: true, Desktop: true})
+type Notifier struct {
+	// Level is the lowest level notified, LevelError when zero.
+	Level Level
+	// Bell writes BEL to Terminal, os.Stderr by default.
+	Bell     bool
+	Terminal io.Writer
+	Desktop  bool
+	// Title of desktop notifications, the program name by default.
+	Title string
+	// Interval is the least time between two notifications, 5s by default;
+	// entries in between are not notified.
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (n *Notifier) WriteEntry(e Entry) error {
+	level := n.Level
+	if level == LevelDebug {
+		level = LevelError
+	}
+	if e.level < level || !n.allow(e.time) {
+		return nil
+	}
+	if n.Bell {
+		w := n.Terminal
+		if w == nil {
+			w = os.Stderr
+		}
+		if _, err := io.WriteString(w, "\a"); err != nil {
+			return err
+		}
+	}
+	if n.Desktop {
+		title := n.Title
+		if title == "" {
+			title = filepath.Base(os.Args[0])
+		}
+		cmd := notifyCommand(title, strings.ToUpper(e.level.String())+": "+e.message)
+		if cmd == nil {
+			return nil
+		}
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go cmd.Wait()
+	}
+	return nil
+}
+
+func (n *Notifier) allow(t time.Time) bool {
+	interval := n.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.last.IsZero() && t.Sub(n.last) < interval {
+		return false
+	}
+	n.last = t
+	return true
+}
+
+// notifyCommand returns the command showing a desktop notification, or nil
+// when the platform has none.
+func notifyCommand(title, msg string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+		return exec.Command("osascript", "-e",
+			`display notification "`+quote(msg)+`" with title "`+quote(title)+`"`)
+	case "windows":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		return exec.Command("powershell", "-NoProfile", "-Command",
+			"Add-Type -AssemblyName System.Windows.Forms; "+
+				"$n = New-Object System.Windows.Forms.NotifyIcon; "+
+				"$n.Icon = [System.Drawing.SystemIcons]::Error; $n.Visible = $true; "+
+				"$n.ShowBalloonTip(5000, "+quote(title)+", "+quote(msg)+", 'Error'); "+
+				"Start-Sleep -Seconds 6; $n.Dispose()")
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return exec.Command("notify-send", "--app-name", title, title, msg)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build ios && cgo
+
+package logger
+
+/*
+#include <stdlib.h>
+#include <os/log.h>
+
+static os_log_t logger_os_log_create(const char *subsystem, const char *category) {
+	if (subsystem[0] == '\0') {
+		return OS_LOG_DEFAULT;
+	}
+	return os_log_create(subsystem, category);
+}
+
+static void logger_os_log(os_log_t log, os_log_type_t type, const char *msg) {
+	os_log_with_type(log, type, "%{public}s", msg);
+}
+*/
+import "C"
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// OSLog is a Sink forwarding entries to the unified logging system on iOS,
+// so they show up in Console.app and `log stream`.
+type OSLog struct {
+	log C.os_log_t
+}
+
+var osLogTypes = map[Level]C.os_log_type_t{
+	LevelDebug: C.OS_LOG_TYPE_DEBUG,
+	LevelInfo:  C.OS_LOG_TYPE_INFO,
+	LevelWarn:  C.OS_LOG_TYPE_DEFAULT,
+	LevelError: C.OS_LOG_TYPE_ERROR,
+	LevelAlert: C.OS_LOG_TYPE_FAULT,
+	LevelPanic: C.OS_LOG_TYPE_FAULT,
+	LevelFatal: C.OS_LOG_TYPE_FAULT,
+}
+
+// NewOSLog creates a sink logging under subsystem (e.g. "com.example.sdk")
+// and category. With an empty subsystem the default log is used.
+func NewOSLog(subsystem, category string) *OSLog {
+	csub := C.CString(subsystem)
+	defer C.free(unsafe.Pointer(csub))
+	ccat := C.CString(category)
+	defer C.free(unsafe.Pointer(ccat))
+	return &OSLog{log: C.logger_os_log_create(csub, ccat)}
+}
+
+func (s *OSLog) WriteEntry(e Entry) error {
+	typ, ok := osLogTypes[e.level]
+	if !ok {
+		typ = C.OS_LOG_TYPE_DEFAULT
+	}
+	msg := joinFields(e.message, e.fields, nil)
+	if e.name != "" {
+		msg = "[" + e.name + "] " + msg
+	}
+	caller := e.Caller()
+	if !caller.IsZero() {
+		msg = caller.Function + ":" + strconv.Itoa(caller.Line) + " " + msg
+	}
+
+	cmsg := C.CString(msg)
+	defer C.free(unsafe.Pointer(cmsg))
+	C.logger_os_log(s.log, typ, cmsg)
+	return nil
+}
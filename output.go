@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// outputMu serializes the writes of every logger, so entries spanning
+// several lines are never interleaved when loggers share a writer.
+var outputMu sync.Mutex
+
+// writeLine writes s and a newline with a single Write call.
+func writeLine(w io.Writer, s string) error {
+	b := make([]byte, 0, len(s)+1)
+	b = append(b, s...)
+	b = append(b, '\n')
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	_, err := w.Write(b)
+	return err
+}
+
+type Format int
+
+const (
+	FormatConsole Format = iota
+	FormatJSON
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatConsole:
+		return "console"
+	case FormatJSON:
+		return "json"
+	}
+	return fmt.Sprintf("format(%d)", int(f))
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "console", "text", "":
+		return FormatConsole, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return FormatConsole, fmt.Errorf("unknown format: %q", s)
+}
+
+// ColorMode chooses when the console output is styled.
+type ColorMode int
+
+const (
+	// ColorAuto styles the output when it is a terminal and NO_COLOR is not
+	// set.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// outputs are the writers of a logger, shared with the loggers derived from
+// it so SetOutput applies to all of them.
+type outputs struct {
+	mu   sync.RWMutex
+	w    io.Writer
+	f    *formatter
+	errW io.Writer // nil when errors go to w
+	ef   *formatter
+}
+
+func (o *outputs) set(c *Config, w io.Writer) {
+	if w == nil {
+		w = os.Stdout
+	}
+	f := newFormatter(c, w)
+	o.mu.Lock()
+	o.w, o.f = w, f
+	o.mu.Unlock()
+}
+
+func (o *outputs) setErrors(c *Config, w io.Writer) {
+	var f *formatter
+	if w != nil {
+		f = newFormatter(c, w)
+	}
+	o.mu.Lock()
+	o.errW, o.ef = w, f
+	o.mu.Unlock()
+}
+
+func (o *outputs) get(lv Level) (io.Writer, *formatter) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if lv >= LevelError && o.errW != nil {
+		return o.errW, o.ef
+	}
+	return o.w, o.f
+}
+
+// SetOutput changes where entries are written, os.Stdout when w is nil.
+// Styling is chosen again for the new writer, as with Config.Output. The
+// loggers derived from l with Tag or WithFields follow.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.o.set(l.c, w)
+}
+
+// SetErrorOutput changes where Error and Alert entries are written. With a
+// nil w they go to the output again.
+func (l *Logger) SetErrorOutput(w io.Writer) {
+	l.o.setErrors(l.c, w)
+}
+
+func (l *Logger) write(e Entry) {
+	w, f := l.o.get(e.level)
+	if l.c.Format == FormatJSON {
+		b, err := json.Marshal(e)
+		if err != nil {
+			if l.c.IsDebugMode {
+				debug("encoding entry err: ", err)
+			}
+			return
+		}
+		writeLine(w, string(b))
+		return
+	}
+	writeLine(w, f.render(e))
+}
+
+// render formats e for the console, in the custom layout when one is set.
+func (f *formatter) render(e Entry) string {
+	if f.custom != nil {
+		return string(f.custom.Format(e))
+	}
+	return f.console(e)
+}
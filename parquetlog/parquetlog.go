@@ -0,0 +1,148 @@
+// Package parquetlog exports entries to Parquet files partitioned by date and
+// level, ready to be queried with DuckDB, Athena or Spark.
+package parquetlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/pecet3/logger"
+)
+
+// Row is the schema of the exported files. Fields holds the structured
+// fields of the entry as a JSON object.
+type Row struct {
+	Time           time.Time `parquet:"time,timestamp(microsecond)"`
+	Seq            uint64    `parquet:"seq"`
+	Level          string    `parquet:"level"`
+	Logger         string    `parquet:"logger,optional"`
+	Message        string    `parquet:"msg"`
+	CallerFunction string    `parquet:"caller_function,optional"`
+	CallerFile     string    `parquet:"caller_file,optional"`
+	CallerLine     int64     `parquet:"caller_line,optional"`
+	Fields         string    `parquet:"fields,optional,json"`
+}
+
+func newRow(e logger.Entry) (Row, error) {
+	c := e.Caller()
+	row := Row{
+		Time:           e.Time(),
+		Seq:            e.Seq(),
+		Level:          e.Level().String(),
+		Logger:         e.Name(),
+		Message:        e.Message(),
+		CallerFunction: c.Function,
+		CallerFile:     c.File,
+		CallerLine:     int64(c.Line),
+	}
+	if len(e.Fields()) == 0 {
+		return row, nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return row, err
+	}
+	var fields struct {
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return row, err
+	}
+	row.Fields = string(fields.Fields)
+	return row, nil
+}
+
+// Exporter is a logger.Sink writing entries under Dir in Hive-style
+// partitions, e.g. Dir/date=2024-03-01/level=error/part-<id>.parquet. Files
+// are only complete once Close returns.
+type Exporter struct {
+	dir string
+	id  string
+
+	mu      sync.Mutex
+	writers map[string]*partition
+}
+
+type partition struct {
+	f *os.File
+	w *parquet.GenericWriter[Row]
+}
+
+func NewExporter(dir string) *Exporter {
+	return &Exporter{
+		dir:     dir,
+		id:      fmt.Sprintf("%d", time.Now().UnixNano()),
+		writers: make(map[string]*partition),
+	}
+}
+
+func (x *Exporter) WriteEntry(e logger.Entry) error {
+	row, err := newRow(e)
+	if err != nil {
+		return err
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	p, err := x.partition(e)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write([]Row{row})
+	return err
+}
+
+func (x *Exporter) partition(e logger.Entry) (*partition, error) {
+	dir := filepath.Join(x.dir,
+		"date="+e.Time().Format("2006-01-02"),
+		"level="+e.Level().String(),
+	)
+	if p, ok := x.writers[dir]; ok {
+		return p, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(dir, "part-"+x.id+".parquet"))
+	if err != nil {
+		return nil, err
+	}
+	p := &partition{f: f, w: parquet.NewGenericWriter[Row](f)}
+	x.writers[dir] = p
+	return p, nil
+}
+
+// Close writes the footers of all files and closes them.
+func (x *Exporter) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var first error
+	for dir, p := range x.writers {
+		err := p.w.Close()
+		if cerr := p.f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("parquetlog: %s: %w", dir, err)
+		}
+		delete(x.writers, dir)
+	}
+	return first
+}
+
+// Export writes all entries of src and closes the files.
+func Export(dir string, src logger.EntrySource) error {
+	x := NewExporter(dir)
+	for _, e := range src.Entries() {
+		if err := x.WriteEntry(e); err != nil {
+			x.Close()
+			return err
+		}
+	}
+	return x.Close()
+}
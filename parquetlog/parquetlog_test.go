@@ -0,0 +1,43 @@
+package parquetlog_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/pecet3/logger"
+	"github.com/pecet3/logger/parquetlog"
+)
+
+type order struct{}
+
+func (order) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddInt("id", 42)
+	enc.AddString("status", "paid")
+}
+
+func TestExport(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: &bytes.Buffer{}, Sinks: []logger.Sink{ring}})
+	l.Info("created", order{})
+	l.Error("failed")
+	l.Info("shipped")
+
+	dir := t.TempDir()
+	if err := parquetlog.Export(dir, ring); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "date=*", "level=info", "*.parquet"))
+	if len(files) != 1 {
+		t.Fatalf("got info partitions %v", files)
+	}
+	rows, err := parquet.ReadFile[parquetlog.Row](files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Message != "created" || rows[0].Fields != `{"id":42,"status":"paid"}` {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+type PoolOptions struct {
+	// Interval is the period between "pool stats" entries and stall checks,
+	// 30 seconds by default.
+	Interval time.Duration
+	// StallAfter is how long a worker may spend on one item before it is
+	// reported as stalled. Zero disables the check.
+	StallAfter time.Duration
+	// Queue returns the number of items waiting, e.g.
+	// func() int { return len(jobs) } for a channel.
+	Queue func() int
+}
+
+// Pool instruments a worker pool: every processed item is logged with its
+// duration (Debug, or Error when it failed), the queue depth and throughput
+// are logged every Interval and workers stuck on an item for longer than
+// StallAfter get a Warn entry. Close stops the reporting.
+type Pool struct {
+	l    *Logger
+	name string
+	opts PoolOptions
+
+	mu       sync.Mutex
+	busy     map[*PoolItem]struct{}
+	done     int // since the last stats entry
+	failed   int
+	total    time.Duration
+	closed   bool
+	stop     chan struct{}
+	stopped  chan struct{}
+	lastTick time.Time
+}
+
+// PoolItem is one item being processed, returned by Pool.Begin.
+type PoolItem struct {
+	p       *Pool
+	worker  int
+	start   time.Time
+	stalled bool
+}
+
+func (l *Logger) Pool(name string, opts PoolOptions) *Pool {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	p := &Pool{
+		l:        l,
+		name:     name,
+		opts:     opts,
+		busy:     make(map[*PoolItem]struct{}),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		lastTick: time.Now(),
+	}
+	go p.report()
+	return p
+}
+
+// Begin marks worker as starting an item. Done must be called when it is
+// processed.
+func (p *Pool) Begin(worker int) *PoolItem {
+	it := &PoolItem{p: p, worker: worker, start: time.Now()}
+	p.mu.Lock()
+	p.busy[it] = struct{}{}
+	p.mu.Unlock()
+	return it
+}
+
+// Done logs the item with its duration, as an Error entry when err is not
+// nil.
+func (it *PoolItem) Done(err error) {
+	p := it.p
+	d := time.Since(it.start)
+	p.mu.Lock()
+	if _, ok := p.busy[it]; !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.busy, it)
+	p.done++
+	p.total += d
+	if err != nil {
+		p.failed++
+	}
+	p.mu.Unlock()
+
+	ev := poolItemEvent{pool: p.name, worker: it.worker, duration: d, err: err}
+	if err != nil {
+		if p.l.enabled(LevelError) {
+			p.l.log(makeEntry(LevelError, "item failed", marshalFields(ev), nil))
+		}
+		return
+	}
+	if p.l.enabled(LevelDebug) {
+		p.l.log(makeEntry(LevelDebug, "item processed", marshalFields(ev), nil))
+	}
+}
+
+// Run processes one item with fn on worker and returns its error.
+func (p *Pool) Run(worker int, fn func() error) error {
+	it := p.Begin(worker)
+	err := fn()
+	it.Done(err)
+	return err
+}
+
+// Close stops the reporting after logging the stats of the last interval.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.mu.Unlock()
+	close(p.stop)
+	<-p.stopped
+}
+
+func (p *Pool) report() {
+	defer close(p.stopped)
+	t := time.NewTicker(p.opts.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stop:
+			p.stats(time.Now())
+			return
+		case now := <-t.C:
+			p.checkStalls(now)
+			p.stats(now)
+		}
+	}
+}
+
+func (p *Pool) stats(now time.Time) {
+	p.mu.Lock()
+	ev := poolStatsEvent{
+		pool:      p.name,
+		queued:    -1,
+		busy:      len(p.busy),
+		processed: p.done,
+		failed:    p.failed,
+		interval:  now.Sub(p.lastTick),
+	}
+	if p.done > 0 {
+		ev.avg = p.total / time.Duration(p.done)
+	}
+	p.done, p.failed, p.total = 0, 0, 0
+	p.lastTick = now
+	p.mu.Unlock()
+
+	if p.opts.Queue != nil {
+		ev.queued = p.opts.Queue()
+	}
+	if p.l.enabled(LevelInfo) {
+		p.l.log(makeEntry(LevelInfo, "pool stats", marshalFields(ev), nil))
+	}
+}
+
+// checkStalls warns once about every item running for longer than
+// StallAfter.
+func (p *Pool) checkStalls(now time.Time) {
+	if p.opts.StallAfter <= 0 {
+		return
+	}
+	var stalled []poolItemEvent
+	p.mu.Lock()
+	for it := range p.busy {
+		if d := now.Sub(it.start); !it.stalled && d >= p.opts.StallAfter {
+			it.stalled = true
+			stalled = append(stalled, poolItemEvent{pool: p.name, worker: it.worker, duration: d})
+		}
+	}
+	p.mu.Unlock()
+	if !p.l.enabled(LevelWarn) {
+		return
+	}
+	for _, ev := range stalled {
+		p.l.log(makeEntry(LevelWarn, "worker stalled", marshalFields(ev), nil))
+	}
+}
+
+type poolItemEvent struct {
+	pool     string
+	worker   int
+	duration time.Duration
+	err      error
+}
+
+func (ev poolItemEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("pool", ev.pool)
+	enc.AddInt("worker", int64(ev.worker))
+	enc.AddDuration("duration", ev.duration)
+	if ev.err != nil {
+		enc.AddString("error", ev.err.Error())
+	}
+}
+
+type poolStatsEvent struct {
+	pool      string
+	queued    int // -1 when unknown
+	busy      int
+	processed int
+	failed    int
+	avg       time.Duration
+	interval  time.Duration
+}
+
+func (ev poolStatsEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("pool", ev.pool)
+	if ev.queued >= 0 {
+		enc.AddInt("queued", int64(ev.queued))
+	}
+	enc.AddInt("busy", int64(ev.busy))
+	enc.AddInt("processed", int64(ev.processed))
+	enc.AddInt("failed", int64(ev.failed))
+	if ev.processed > 0 {
+		enc.AddDuration("avg", ev.avg)
+	}
+	enc.AddDuration("interval", ev.interval)
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EntrySource is anything holding entries that can be queried, such as a
+// Ring.
+type EntrySource interface {
+	Entries() []Entry
+}
+
+// EntrySlice makes a plain slice of entries, e.g. read with DecodeStream,
+// usable as an EntrySource.
+type EntrySlice []Entry
+
+func (s EntrySlice) Entries() []Entry {
+	return s
+}
+
+type QueryResult struct {
+	entries []Entry
+	err     error
+}
+
+// Query selects the entries of src matching the filter expression (see
+// Filter). An empty expression selects everything. Errors in the expression
+// are reported by the methods of the result.
+func Query(src EntrySource, expr string) *QueryResult {
+	var f *Filter
+	if expr != "" {
+		var err error
+		if f, err = CompileFilter(expr); err != nil {
+			return &QueryResult{err: err}
+		}
+	}
+	var out []Entry
+	for _, e := range src.Entries() {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return &QueryResult{entries: out}
+}
+
+func (r *QueryResult) Entries() ([]Entry, error) {
+	return r.entries, r.err
+}
+
+func (r *QueryResult) Err() error {
+	return r.err
+}
+
+// WriteCSV writes the entries as CSV with a header row: time, level, logger,
+// message and caller, followed by one column per field path such as
+// "tenant" or "user.id". Missing fields are left empty.
+func (r *QueryResult) WriteCSV(w io.Writer, fields ...string) error {
+	return r.writeTable(w, ',', fields)
+}
+
+// WriteTSV is WriteCSV with tab separated columns.
+func (r *QueryResult) WriteTSV(w io.Writer, fields ...string) error {
+	return r.writeTable(w, '\t', fields)
+}
+
+func (r *QueryResult) writeTable(w io.Writer, comma rune, fields []string) error {
+	if r.err != nil {
+		return r.err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	header := append([]string{"time", "level", "logger", "message", "caller"}, fields...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	paths := make([][]string, len(fields))
+	for i, f := range fields {
+		paths[i] = strings.Split(f, ".")
+	}
+	record := make([]string, len(header))
+	for _, e := range r.entries {
+		record[0] = e.time.Format(time.RFC3339Nano)
+		record[1] = e.level.String()
+		record[2] = e.name
+		record[3] = e.message
+		record[4] = ""
+		caller := e.Caller()
+		if !caller.IsZero() {
+			record[4] = caller.Function + ":" + strconv.Itoa(caller.Line)
+		}
+		for i, path := range paths {
+			record[5+i] = ""
+			if v, ok := lookupField(e.fields, path); ok {
+				record[5+i] = tableValue(v)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func tableValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case error:
+		return v.Error()
+	case []Field:
+		b, err := marshalFieldsJSON(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
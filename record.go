@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const recordingFormat = "logger.recording"
+
+type recordingHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	Started    time.Time `json:"started"`
+	Dictionary bool      `json:"dictionary,omitempty"`
+}
+
+type RecorderOptions struct {
+	// Dictionary stores repeated messages once as a template referenced by
+	// the entries, which shrinks recordings of chatty services. Readers
+	// resolve the messages transparently.
+	Dictionary bool
+}
+
+// Recorder is a Sink that stores every entry, with its full timing, in a
+// gzip-compressed file which can later be re-rendered with Playback.
+type Recorder struct {
+	mu   sync.Mutex
+	f    *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+	dict *dictEncoder
+	buf  []byte
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	return NewRecorderOptions(path, RecorderOptions{})
+}
+
+func NewRecorderOptions(path string, opts RecorderOptions) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	r := &Recorder{
+		f:   f,
+		gz:  gz,
+		enc: json.NewEncoder(gz),
+	}
+	if opts.Dictionary {
+		r.dict = &dictEncoder{}
+	}
+	version := 1
+	if opts.Dictionary {
+		version = 2
+	}
+	if err := r.enc.Encode(recordingHeader{
+		Format:     recordingFormat,
+		Version:    version,
+		Started:    time.Now(),
+		Dictionary: opts.Dictionary,
+	}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Recorder) WriteEntry(e Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dict == nil {
+		return r.enc.Encode(e)
+	}
+	var err error
+	if r.buf, err = r.dict.appendEntry(r.buf[:0], e); err != nil {
+		return err
+	}
+	_, err = r.gz.Write(r.buf)
+	return err
+}
+
+// Flush pushes buffered entries to the file without closing the recording.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gz.Flush()
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.gz.Close(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+type RecordingReader struct {
+	gz      *gzip.Reader
+	dec     *json.Decoder
+	dict    dictDecoder
+	version int
+}
+
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("not a recording: %w", err)
+	}
+	dec := json.NewDecoder(gz)
+	var h recordingHeader
+	if err := dec.Decode(&h); err != nil {
+		gz.Close()
+		return nil, fmt.Errorf("reading recording header: %w", err)
+	}
+	if h.Format != recordingFormat {
+		gz.Close()
+		return nil, errors.New("not a recording: unknown format")
+	}
+	if h.Version == 0 {
+		h.Version = 1
+	}
+	if h.Version > SchemaVersion {
+		gz.Close()
+		return nil, fmt.Errorf("recording schema v%d is newer than supported v%d", h.Version, SchemaVersion)
+	}
+	return &RecordingReader{gz: gz, dec: dec, version: h.Version}, nil
+}
+
+// Version is the schema version the recording was written with. Entries
+// returned by Next are always migrated to SchemaVersion.
+func (rr *RecordingReader) Version() int {
+	return rr.version
+}
+
+// Next returns the next recorded entry, or io.EOF once the recording ends.
+func (rr *RecordingReader) Next() (Entry, error) {
+	for {
+		var line json.RawMessage
+		if err := rr.dec.Decode(&line); err != nil {
+			return Entry{}, err
+		}
+		e, ok, err := rr.dict.decode(line)
+		if err != nil || ok {
+			return e, err
+		}
+	}
+}
+
+func (rr *RecordingReader) Close() error {
+	return rr.gz.Close()
+}
+
+type PlaybackOptions struct {
+	// Level is the lowest level rendered.
+	Level Level
+	// Filter, when set, skips entries for which it returns false.
+	Filter func(Entry) bool
+	// Speed replays entries with their original pacing divided by Speed.
+	// Zero renders everything immediately.
+	Speed float64
+	// Config, when set, renders entries the way a Logger built from it would.
+	Config *Config
+	// BackfillTime is the same as in DecodeOptions.
+	BackfillTime bool
+}
+
+// Playback renders a recording made by Recorder to w using the console
+// formatter.
+func Playback(r io.Reader, w io.Writer, opts PlaybackOptions) error {
+	rr, err := NewRecordingReader(r)
+	if err != nil {
+		return err
+	}
+	defer rr.Close()
+
+	c := opts.Config
+	if c == nil {
+		c = &Config{}
+	}
+	f := newFormatter(c, w)
+	var last time.Time
+	for {
+		e, err := rr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		backfillTime(&e, time.Now(), opts.BackfillTime)
+		if e.level < opts.Level || (opts.Filter != nil && !opts.Filter(e)) {
+			continue
+		}
+		if opts.Speed > 0 && !last.IsZero() {
+			if gap := e.time.Sub(last); gap > 0 {
+				time.Sleep(time.Duration(float64(gap) / opts.Speed))
+			}
+		}
+		last = e.time
+		if _, err := fmt.Fprintln(w, f.console(e)); err != nil {
+			return err
+		}
+	}
+}
+
+func PlaybackFile(path string, w io.Writer, opts PlaybackOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Playback(f, w, opts)
+}
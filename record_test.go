@@ -0,0 +1,142 @@
+package logger_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pecet3/logger"
+)
+
+func TestRecorder_Playback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incident.rec")
+	rec, err := logger.NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := logger.New(&logger.Config{
+		Duration: time.Hour,
+		Sinks:    []logger.Sink{rec},
+	})
+	l.Info("service started")
+	l.Warn("slow response")
+	l.Error("connection lost")
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = logger.PlaybackFile(path, &out, logger.PlaybackOptions{Level: logger.LevelWarn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if strings.Contains(got, "service started") {
+		t.Errorf("info entry should be filtered out:\n%s", got)
+	}
+	if !strings.Contains(got, "slow response") || !strings.Contains(got, "connection lost") {
+		t.Errorf("missing entries in playback:\n%s", got)
+	}
+	if !strings.Contains(got, "TestRecorder_Playback") {
+		t.Errorf("error entry should keep its caller:\n%s", got)
+	}
+}
+
+func TestEntry_UnmarshalSchemaVersions(t *testing.T) {
+	var e logger.Entry
+	legacy := `{"time":"2025-01-09T10:00:00Z","level":"warn","msg":"disk almost full"}`
+	if err := json.Unmarshal([]byte(legacy), &e); err != nil {
+		t.Fatalf("unversioned entry should decode as v1: %v", err)
+	}
+	if e.Level() != logger.LevelWarn || e.Message() != "disk almost full" {
+		t.Errorf("unexpected entry: %v %q", e.Level(), e.Message())
+	}
+
+	future := `{"v":99,"time":"2025-01-09T10:00:00Z","level":"warn","msg":"x"}`
+	if err := json.Unmarshal([]byte(future), &e); err == nil {
+		t.Error("expected an error for an unsupported schema version")
+	}
+}
+
+func TestEntry_Migrations(t *testing.T) {
+	// Pretend v1 entries had their message under "message".
+	logger.SetEntryMigration(t, 1, func(raw map[string]json.RawMessage) error {
+		raw["msg"] = raw["message"]
+		delete(raw, "message")
+		return nil
+	})
+	const line = `{"v":1,"time":"2025-01-09T10:00:00Z","level":"info","message":"old layout"}` + "\n"
+
+	entries, errc := logger.DecodeStream(strings.NewReader(line))
+	for e := range entries {
+		if e.Message() != "old layout" {
+			t.Errorf("DecodeStream: got message %q", e.Message())
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	logger.Follow(path, &out, logger.FollowOptions{Context: ctx, FromStart: true, Poll: 5 * time.Millisecond})
+	// Lines that are not entries are copied as they are.
+	if got := out.String(); !strings.Contains(got, "old layout") || strings.Contains(got, `"message"`) {
+		t.Errorf("Follow should render the migrated entry:\n%s", got)
+	}
+
+	var rec bytes.Buffer
+	gz := gzip.NewWriter(&rec)
+	io.WriteString(gz, `{"format":"logger.recording","version":1,"started":"2025-01-09T10:00:00Z"}`+"\n"+line)
+	gz.Close()
+	rr, err := logger.NewRecordingReader(&rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := rr.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.Version() != 1 || e.Message() != "old layout" {
+		t.Errorf("recording v%d: got message %q", rr.Version(), e.Message())
+	}
+}
+
+func TestRecorder_Dictionary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chatty.rec")
+	rec, err := logger.NewRecorderOptions(path, logger.RecorderOptions{Dictionary: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{rec}})
+	for i := 0; i < 3; i++ {
+		l.Info(fmt.Sprintf("request %d served", i))
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := logger.PlaybackFile(path, &out, logger.PlaybackOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if want := fmt.Sprintf("request %d served", i); !strings.Contains(out.String(), want) {
+			t.Errorf("playback does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Retention deletes the oldest files of a log directory once they are too
+// old or the directory grows too large. Every regular file under Dir
+// matching Pattern is treated as a rotated file, so Dir should not hold
+// files that are still being written, except by the FileSink running it:
+// the files that sink has open are skipped.
+type Retention struct {
+	Dir string
+	// Pattern is matched against file names, all files when empty.
+	Pattern string
+	// MaxBytes caps the total size of the matching files.
+	MaxBytes int64
+	// MaxAge removes files last modified longer ago.
+	MaxAge time.Duration
+	// DryRun only reports the files that would be deleted.
+	DryRun bool
+	// Logger, when set, receives an entry for every deleted file.
+	Logger *Logger
+	// WORM, when set, keeps files within its retention period.
+	WORM *WORM
+
+	mu sync.Mutex
+}
+
+type retainedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+type retentionEvent struct {
+	file   retainedFile
+	reason string
+	dryRun bool
+}
+
+func (ev retentionEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("path", ev.file.path)
+	enc.AddInt("size", ev.file.size)
+	enc.AddTime("modified", ev.file.modTime)
+	enc.AddString("reason", ev.reason)
+	if ev.dryRun {
+		enc.AddBool("dry_run", true)
+	}
+}
+
+// Enforce deletes the files exceeding the limits, oldest first, and returns
+// their paths. In DryRun mode nothing is deleted.
+func (r *Retention) Enforce() ([]string, error) {
+	return r.enforce(nil)
+}
+
+// enforceLogged enforces the retention in the background of a FileSink,
+// logging the error it fails with.
+func (r *Retention) enforceLogged(open func(path string) bool) {
+	if _, err := r.enforce(open); err != nil && r.Logger != nil {
+		r.Logger.ErrorErr(err, "enforcing log retention failed")
+	}
+}
+
+// enforce is Enforce leaving out the files open reports as open.
+func (r *Retention) enforce(open func(path string) bool) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	files, err := scanFiles(r.Dir, r.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		kept := files[:0]
+		for _, f := range files {
+			if !open(f.path) {
+				kept = append(kept, f)
+			}
+		}
+		files = kept
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+
+	var deleted []string
+	now := time.Now()
+	for _, f := range files {
+		reason := ""
+		switch {
+		case r.MaxAge > 0 && now.Sub(f.modTime) > r.MaxAge:
+			reason = "max_age"
+		case r.MaxBytes > 0 && total > r.MaxBytes:
+			reason = "max_bytes"
+		default:
+			continue
+		}
+		if !r.DryRun {
+			if !r.WORM.allowDelete(f.path, "retention "+reason) {
+				continue
+			}
+			if err := os.Remove(f.path); err != nil {
+				r.warn("removing log file err: ", err)
+				continue
+			}
+			r.removeEmptyDirs(filepath.Dir(f.path))
+		}
+		total -= f.size
+		deleted = append(deleted, f.path)
+		if r.Logger != nil {
+			msg := "deleted log file"
+			if r.DryRun {
+				msg = "would delete log file"
+			}
+			r.Logger.Info(msg, retentionEvent{file: f, reason: reason, dryRun: r.DryRun})
+		}
+	}
+	return deleted, nil
+}
+
+// scanFiles returns the regular files under dir whose name matches pattern,
+// all of them when it is empty.
+func scanFiles(dir, pattern string) ([]retainedFile, error) {
+	var files []retainedFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if pattern != "" {
+			if ok, err := filepath.Match(pattern, d.Name()); err != nil || !ok {
+				return err
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, retainedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// removeEmptyDirs removes dir and its parents up to Dir while they are empty,
+// e.g. the per-day directories of a FileSink.
+func (r *Retention) removeEmptyDirs(dir string) {
+	root := filepath.Clean(r.Dir)
+	for dir != root && len(dir) > len(root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func (r *Retention) warn(args ...interface{}) {
+	if r.Logger != nil {
+		r.Logger.Warn(args...)
+	}
+}
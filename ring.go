@@ -0,0 +1,50 @@
+package logger
+
+import "sync"
+
+// Ring is a Sink keeping the most recent entries in a fixed-size buffer.
+type Ring struct {
+	mu   sync.Mutex
+	buf  []Entry
+	next int
+	full bool
+}
+
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = 1
+	}
+	return &Ring{buf: make([]Entry, size)}
+}
+
+func (r *Ring) WriteEntry(e Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf[r.next] = e
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+// Entries returns a copy of the buffered entries, oldest first.
+func (r *Ring) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.buf[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+func (r *Ring) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.buf)
+	}
+	return r.next
+}
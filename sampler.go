@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sampler keeps every entry while the system is failing and only a fraction
+// of Info and Debug entries while it is healthy, so incidents are recorded
+// in full without paying for the noise the rest of the time. Warn and above
+// are never dropped.
+//
+// The error rate is the share of Error and Alert entries over the last
+// Window. Sampling stops once it reaches High and resumes only after it fell
+// to Low, so a rate hovering around the threshold does not flap.
+type Sampler struct {
+	// Rate is the fraction of Info and Debug entries kept while healthy,
+	// e.g. 0.05. Zero drops all of them.
+	Rate float64
+	// High is the error rate entering the elevated state, 5% by default.
+	High float64
+	// Low is the error rate returning to sampling, half of High by default.
+	Low float64
+	// Window is the period the error rate is measured over, one minute by
+	// default.
+	Window time.Duration
+
+	mu              sync.Mutex
+	start           time.Time
+	total, errors   int // current window
+	pTotal, pErrors int // previous window
+	elevated        bool
+}
+
+// Elevated reports whether the error rate is high enough for every entry to
+// be kept.
+func (s *Sampler) Elevated() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elevated
+}
+
+// keep counts e towards the error rate and reports whether it is logged.
+func (s *Sampler) keep(e Entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance(e.time)
+	s.total++
+	if e.level >= LevelError {
+		s.errors++
+	}
+
+	high := s.High
+	if high <= 0 {
+		high = 0.05
+	}
+	low := s.Low
+	if low <= 0 || low > high {
+		low = high / 2
+	}
+	rate := s.rate(e.time)
+	switch {
+	case !s.elevated && rate >= high:
+		s.elevated = true
+	case s.elevated && rate <= low:
+		s.elevated = false
+	}
+
+	if s.elevated || e.level >= LevelWarn {
+		return true
+	}
+	return s.Rate > 0 && rand.Float64() < s.Rate
+}
+
+func (s *Sampler) window() time.Duration {
+	if s.Window <= 0 {
+		return time.Minute
+	}
+	return s.Window
+}
+
+// advance starts a new window once the current one is over.
+func (s *Sampler) advance(now time.Time) {
+	w := s.window()
+	switch elapsed := now.Sub(s.start); {
+	case s.start.IsZero():
+		s.start = now
+	case elapsed >= 2*w:
+		s.start = now
+		s.total, s.errors, s.pTotal, s.pErrors = 0, 0, 0, 0
+	case elapsed >= w:
+		s.start = s.start.Add(w)
+		s.pTotal, s.pErrors = s.total, s.errors
+		s.total, s.errors = 0, 0
+	}
+}
+
+// rate weighs the previous window by how much of it still overlaps the
+// last Window.
+func (s *Sampler) rate(now time.Time) float64 {
+	w := s.window()
+	overlap := 1 - float64(now.Sub(s.start))/float64(w)
+	overlap = min(max(overlap, 0), 1)
+	total := float64(s.total) + float64(s.pTotal)*overlap
+	if total == 0 {
+		return 0
+	}
+	return (float64(s.errors) + float64(s.pErrors)*overlap) / total
+}
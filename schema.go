@@ -0,0 +1,78 @@
+package logger
+
+import "sync"
+
+// schemaArena is the number of entries whose fields a Schema allocates at
+// once.
+const schemaArena = 64
+
+// Schema emits entries with a fixed message and set of field keys, for hot
+// paths logging the same shape over and over. The field slices of entries
+// are carved out of a shared block allocated for many entries at a time, and
+// the message is never formatted.
+type Schema struct {
+	l       *Logger
+	message string
+	keys    []string
+
+	mu    sync.Mutex
+	arena []Field
+}
+
+// Schema returns an emitter of entries with the given message whose values
+// are matched with keys by position:
+//
+//	access := l.Schema("http_access", "method", "path", "status")
+//	access.Info(r.Method, r.URL.Path, status)
+func (l *Logger) Schema(message string, keys ...string) *Schema {
+	return &Schema{l: l, message: message, keys: keys}
+}
+
+// fields pairs values with the keys. Keys without a value are left out and
+// extra values are ignored.
+func (s *Schema) fields(values []interface{}) []Field {
+	n := min(len(values), len(s.keys))
+	if n == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	if len(s.arena) < n {
+		s.arena = make([]Field, schemaArena*len(s.keys))
+	}
+	fields := s.arena[:n:n]
+	s.arena = s.arena[n:]
+	s.mu.Unlock()
+
+	for i := range fields {
+		fields[i] = Field{Key: s.keys[i], Value: values[i]}
+	}
+	return fields
+}
+
+func (s *Schema) Error(values ...interface{}) {
+	if !s.l.enabled(LevelError) {
+		return
+	}
+	s.l.log(makeEntry(LevelError, s.message, s.fields(values), s.l.caller(LevelError)))
+}
+
+func (s *Schema) Warn(values ...interface{}) {
+	if !s.l.enabled(LevelWarn) {
+		return
+	}
+	s.l.log(makeEntry(LevelWarn, s.message, s.fields(values), nil))
+}
+
+func (s *Schema) Info(values ...interface{}) {
+	if !s.l.enabled(LevelInfo) {
+		return
+	}
+	s.l.log(makeEntry(LevelInfo, s.message, s.fields(values), nil))
+}
+
+func (s *Schema) Debug(values ...interface{}) {
+	if !s.l.enabled(LevelDebug) {
+		return
+	}
+	s.l.log(makeEntry(LevelDebug, s.message, s.fields(values), s.l.caller(LevelDebug)))
+}
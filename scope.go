@@ -0,0 +1,82 @@
+package logger
+
+import "sync"
+
+// Scope logs Info and above right away but holds the latest Debug entries
+// of an operation, emitting them only if the operation fails. Held entries
+// are emitted even when the logger level is above Debug, so error reports
+// come with verbose context that successful operations never pay for.
+type Scope struct {
+	l *Logger
+
+	mu    sync.Mutex
+	debug *Ring
+	ended bool
+}
+
+var _ Interface = (*Scope)(nil)
+
+// Scope starts a scope holding up to size Debug entries; older ones are
+// dropped.
+func (l *Logger) Scope(size int) *Scope {
+	return &Scope{l: l, debug: NewRing(size)}
+}
+
+// End closes the scope. When err is not nil the held Debug entries are
+// logged, followed by an Error entry with err. Entries logged after End are
+// logged as they would be by the logger.
+func (s *Scope) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.mu.Unlock()
+	if err == nil {
+		return
+	}
+	for _, e := range s.debug.Entries() {
+		s.l.log(e)
+	}
+	if s.l.enabled(LevelError) {
+		s.l.log(newEntry(LevelError, []interface{}{err}, s.l.caller(LevelError)))
+	}
+}
+
+func (s *Scope) Debug(args ...interface{}) {
+	s.mu.Lock()
+	ended := s.ended
+	s.mu.Unlock()
+	if ended {
+		if s.l.enabled(LevelDebug) {
+			s.l.log(newEntry(LevelDebug, args, s.l.caller(LevelDebug)))
+		}
+		return
+	}
+	if s.l.nop {
+		return
+	}
+	s.debug.WriteEntry(newEntry(LevelDebug, args, s.l.caller(LevelDebug)))
+}
+
+func (s *Scope) Info(args ...interface{}) {
+	if !s.l.enabled(LevelInfo) {
+		return
+	}
+	s.l.log(newEntry(LevelInfo, args, nil))
+}
+
+func (s *Scope) Warn(args ...interface{}) {
+	if !s.l.enabled(LevelWarn) {
+		return
+	}
+	s.l.log(newEntry(LevelWarn, args, nil))
+}
+
+func (s *Scope) Error(args ...interface{}) {
+	if !s.l.enabled(LevelError) {
+		return
+	}
+	s.l.log(newEntry(LevelError, args, s.l.caller(LevelError)))
+}
@@ -0,0 +1,17 @@
+package logger
+
+// Sink receives every entry logged through a Logger, right after it is
+// printed to the console.
+type Sink interface {
+	WriteEntry(e Entry) error
+}
+
+// Route sends the entries matching Filter (all entries when nil) to Sinks,
+// in addition to the console and Config.Sinks. An Exclusive route sends them
+// only to its own sinks; routes are checked in order and evaluation stops at
+// the first matching exclusive one.
+type Route struct {
+	Filter    *Filter
+	Sinks     []Sink
+	Exclusive bool
+}
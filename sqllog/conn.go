@@ -0,0 +1,259 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type conn struct {
+	driver.Conn
+	w *wrapper
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var s driver.Stmt
+	var err error
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = cp.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: s, query: query, w: c.w}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return cb.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("sqllog: driver does not support transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ex, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := ex.ExecContext(ctx, query, args)
+	c.w.log(query, args, start, rowsAffected(res, err), err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	r, err := q.QueryContext(ctx, query, args)
+	if err != nil {
+		c.w.log(query, args, start, -1, err)
+		return nil, err
+	}
+	return newRows(r, c.w, query, args, start), nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func rowsAffected(res driver.Result, err error) int64 {
+	if err != nil || res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+type stmt struct {
+	driver.Stmt
+	query string
+	w     *wrapper
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if se, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = se.ExecContext(ctx, args)
+	} else {
+		res, err = s.Stmt.Exec(values(args))
+	}
+	s.w.log(s.query, args, start, rowsAffected(res, err), err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var r driver.Rows
+	var err error
+	if sq, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		r, err = sq.QueryContext(ctx, args)
+	} else {
+		r, err = s.Stmt.Query(values(args))
+	}
+	if err != nil {
+		s.w.log(s.query, args, start, -1, err)
+		return nil, err
+	}
+	return newRows(r, s.w, s.query, args, start), nil
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nv
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	v := make([]driver.Value, len(args))
+	for i, a := range args {
+		v[i] = a.Value
+	}
+	return v
+}
+
+// rows logs the query once the result set is closed, so the duration and
+// row count cover reading the results.
+type rows struct {
+	driver.Rows
+	w     *wrapper
+	query string
+	args  []driver.NamedValue
+	start time.Time
+	n     int64
+	err   error
+	once  sync.Once
+}
+
+func newRows(r driver.Rows, w *wrapper, query string, args []driver.NamedValue, start time.Time) *rows {
+	return &rows{Rows: r, w: w, query: query, args: args, start: start}
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.n++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		r.w.log(r.query, r.args, r.start, r.n, r.err)
+	})
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *rows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *rows) ColumnTypeLength(index int) (length int64, ok bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,42 @@
+package sqllog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlowLog writes queries in the MySQL slow query log format, so tools such
+// as pt-query-digest can analyze them.
+type SlowLog struct {
+	W io.Writer
+	// User and Host fill the "# User@Host:" line.
+	User string
+	Host string
+
+	mu sync.Mutex
+}
+
+func (s *SlowLog) write(start time.Time, ev queryEvent) {
+	query := strings.TrimSpace(ev.query)
+	if !strings.HasSuffix(query, ";") {
+		query += ";"
+	}
+	sent := ev.rows
+	if sent < 0 {
+		sent = 0
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Time: %s\n", start.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	fmt.Fprintf(&b, "# User@Host: %s[%s] @ %s []\n", s.User, s.User, s.Host)
+	fmt.Fprintf(&b, "# Query_time: %.6f  Lock_time: 0.000000 Rows_sent: %d  Rows_examined: 0\n", ev.duration.Seconds(), sent)
+	fmt.Fprintf(&b, "SET timestamp=%d;\n", start.Unix())
+	b.WriteString(query)
+	b.WriteByte('\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.W, b.String())
+}
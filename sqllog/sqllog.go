@@ -0,0 +1,124 @@
+// Package sqllog wraps database/sql drivers to log every query with its
+// duration, flagging slow and failed ones.
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/pecet3/logger"
+)
+
+type Options struct {
+	// SlowThreshold logs queries taking at least this long at Warn level.
+	// Zero disables the check.
+	SlowThreshold time.Duration
+	// LogArgs attaches the query arguments to entries.
+	LogArgs bool
+	// SlowLog, when set, also receives the slow queries (all of them when
+	// SlowThreshold is zero) in MySQL slow query log format.
+	SlowLog *SlowLog
+}
+
+type wrapper struct {
+	l    *logger.Logger
+	opts Options
+}
+
+// Wrap returns a connector logging the queries of c, for use with
+// sql.OpenDB.
+func Wrap(c driver.Connector, l *logger.Logger, opts Options) driver.Connector {
+	return &connector{Connector: c, w: &wrapper{l: l, opts: opts}}
+}
+
+// WrapDriver returns a driver logging the queries of d, to be registered
+// with sql.Register under a new name.
+func WrapDriver(d driver.Driver, l *logger.Logger, opts Options) driver.Driver {
+	return &wrappedDriver{Driver: d, w: &wrapper{l: l, opts: opts}}
+}
+
+type connector struct {
+	driver.Connector
+	w *wrapper
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: dc, w: c.w}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return &wrappedDriver{Driver: c.Connector.Driver(), w: c.w}
+}
+
+type wrappedDriver struct {
+	driver.Driver
+	w *wrapper
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	dc, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: dc, w: d.w}, nil
+}
+
+type queryEvent struct {
+	query    string
+	args     []driver.NamedValue
+	logArgs  bool
+	duration time.Duration
+	rows     int64
+	err      error
+}
+
+func (ev queryEvent) MarshalLog(enc logger.FieldEncoder) {
+	enc.AddString("query", ev.query)
+	enc.AddDuration("duration", ev.duration)
+	if ev.rows >= 0 {
+		enc.AddInt("rows", ev.rows)
+	}
+	if ev.logArgs && len(ev.args) > 0 {
+		args := make([]interface{}, len(ev.args))
+		for i, a := range ev.args {
+			args[i] = a.Value
+		}
+		enc.AddAny("args", args)
+	}
+	if ev.err != nil {
+		enc.AddString("error", ev.err.Error())
+	}
+}
+
+// log records a finished query. rows is -1 when unknown.
+func (w *wrapper) log(query string, args []driver.NamedValue, start time.Time, rows int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	ev := queryEvent{
+		query:    query,
+		args:     args,
+		logArgs:  w.opts.LogArgs,
+		duration: time.Since(start),
+		rows:     rows,
+		err:      err,
+	}
+	slow := ev.duration >= w.opts.SlowThreshold
+	if w.opts.SlowLog != nil && slow && err == nil {
+		w.opts.SlowLog.write(start, ev)
+	}
+	switch {
+	case err != nil:
+		w.l.Error("query failed", ev)
+	case slow && w.opts.SlowThreshold > 0:
+		w.l.Warn("slow query", ev)
+	default:
+		w.l.Debug("query", ev)
+	}
+}
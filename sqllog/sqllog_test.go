@@ -0,0 +1,107 @@
+package sqllog_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pecet3/logger"
+	"github.com/pecet3/logger/sqllog"
+)
+
+// fakeConn answers every query with two rows and fails on "BAD".
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "BAD") {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(3), nil
+}
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(2 * time.Millisecond)
+	return &fakeRows{left: 2}, nil
+}
+
+type fakeRows struct{ left int }
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.left == 0 {
+		return io.EOF
+	}
+	dest[0] = int64(r.left)
+	r.left--
+	return nil
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return nil }
+
+func TestWrap(t *testing.T) {
+	ring := logger.NewRing(10)
+	l := logger.New(&logger.Config{Output: io.Discard, Sinks: []logger.Sink{ring}})
+	var slow bytes.Buffer
+	db := sql.OpenDB(sqllog.Wrap(fakeConnector{}, l, sqllog.Options{
+		SlowThreshold: time.Millisecond,
+		LogArgs:       true,
+		SlowLog:       &sqllog.SlowLog{W: &slow, User: "app", Host: "localhost"},
+	}))
+	defer db.Close()
+
+	if _, err := db.Exec("UPDATE users SET active = ?", true); err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("BAD QUERY")
+	rows, err := db.Query("SELECT n FROM numbers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	for i, want := range []struct {
+		level logger.Level
+		msg   string
+	}{
+		{logger.LevelDebug, "query"},
+		{logger.LevelError, "query failed"},
+		{logger.LevelWarn, "slow query"},
+	} {
+		if entries[i].Level() != want.level || entries[i].Message() != want.msg {
+			t.Errorf("entry %d: got %v %q, want %v %q", i, entries[i].Level(), entries[i].Message(), want.level, want.msg)
+		}
+	}
+	if n, _ := logger.Query(ring, `fields.rows == 2 && fields.query startswith "SELECT"`).Entries(); len(n) != 1 {
+		t.Error("the slow query should report the rows read")
+	}
+
+	got := slow.String()
+	for _, want := range []string{"# Time: ", "# User@Host: app[app] @ localhost []\n", "Rows_sent: 2", "SET timestamp=", "SELECT n FROM numbers;\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("slow log %q does not contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "UPDATE") {
+		t.Error("fast queries should not be in the slow log")
+	}
+}
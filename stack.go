@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultStackDepth is the number of frames kept when
+// Config.StackTraceDepth is not set.
+const defaultStackDepth = 32
+
+// stackTrace is a formatted stack trace field, printed on its own lines
+// below the console entry.
+type stackTrace string
+
+// captureStack formats the stack of the calling goroutine, starting at the
+// caller of the logger and leaving out skip more frames.
+func captureStack(skip, depth int) stackTrace {
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+	pcs := make([]uintptr, depth+skip+16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	inLogger := true
+	for kept := 0; kept < depth; {
+		f, more := frames.Next()
+		switch {
+		case inLogger && strings.HasPrefix(f.Function, "github.com/pecet3/logger."):
+		case skip > 0:
+			inLogger = false
+			skip--
+		default:
+			inLogger = false
+			writeFrame(&b, f, kept > 0)
+			kept++
+		}
+		if !more {
+			break
+		}
+	}
+	return stackTrace(b.String())
+}
+
+// callersStack formats the program counters of a stack, as returned by
+// runtime.Callers.
+func callersStack(pcs []uintptr) stackTrace {
+	frames := runtime.CallersFrames(pcs)
+	var b strings.Builder
+	for more, n := true, 0; more && n < defaultStackDepth; n++ {
+		var f runtime.Frame
+		f, more = frames.Next()
+		writeFrame(&b, f, n > 0)
+	}
+	return stackTrace(b.String())
+}
+
+func writeFrame(b *strings.Builder, f runtime.Frame, newline bool) {
+	if newline {
+		b.WriteByte('\n')
+	}
+	b.WriteString(f.Function + "\n\t" + f.File + ":" + strconv.Itoa(f.Line))
+}
+
+func (e Entry) hasField(key string) bool {
+	for _, f := range e.fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// blockLines returns the stack traces and dumps among fields, indented
+// below the entry. Those of nested objects are headed by their dotted key.
+func blockLines(fields []Field, t *Theme) string {
+	var b strings.Builder
+	writeBlocks(&b, "", fields, t)
+	return b.String()
+}
+
+func writeBlocks(b *strings.Builder, prefix string, fields []Field, t *Theme) {
+	for _, f := range fields {
+		key := f.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		var block string
+		switch v := f.Value.(type) {
+		case []Field:
+			writeBlocks(b, key, v, t)
+			continue
+		case stackTrace:
+			block = string(v)
+		case dumpValue:
+			block = dump(v.v, t)
+		default:
+			continue
+		}
+		if prefix != "" {
+			b.WriteString("\n  " + t.FieldKey.render(key+":"))
+		}
+		for _, line := range strings.Split(block, "\n") {
+			b.WriteString("\n    " + line)
+		}
+	}
+}
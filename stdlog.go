@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"log"
+	"runtime"
+	"strings"
+)
+
+// HijackStdlib routes the output of the default logger of the standard
+// log package through l, so stray log.Printf calls of dependencies become
+// entries of the given level. The log prefix, e.g. "[db] ", becomes the
+// name of the entries, a leading level such as "ERROR:" or "[warn]"
+// overrides level, and the caller is the function that called log. The
+// returned function restores the previous output, flags and prefix.
+func HijackStdlib(l *Logger, level Level) (restore func()) {
+	w, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(&stdlibWriter{l: l, level: level})
+	// Entries have their own time and caller.
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
+
+type stdlibWriter struct {
+	l     *Logger
+	level Level
+}
+
+func (w *stdlibWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	l := w.l
+	if prefix := log.Prefix(); prefix != "" && log.Flags()&log.Lmsgprefix == 0 {
+		msg = strings.TrimPrefix(msg, prefix)
+		if name := strings.Trim(prefix, "[]: \t"); name != "" {
+			l = l.Named(name)
+		}
+	}
+	level, msg := stdlibLevel(msg, w.level)
+	if !l.enabled(level) {
+		return len(p), nil
+	}
+	var caller *callerRef
+	if level >= l.c.CallerLevel {
+		caller = stdlibCaller()
+	}
+	l.log(makeEntry(level, msg, nil, caller))
+	return len(p), nil
+}
+
+// stdlibLevel takes a leading level such as "ERROR:", "[warn]" or
+// "WARNING" off msg.
+func stdlibLevel(msg string, def Level) (Level, string) {
+	word := msg
+	if i := strings.IndexAny(msg, " \t"); i >= 0 {
+		word = msg[:i]
+	}
+	bracketed := strings.HasPrefix(word, "[") && strings.HasSuffix(word, "]")
+	name := strings.ToLower(strings.TrimRight(strings.Trim(word, "[]"), ":"))
+	if !bracketed && !strings.HasSuffix(word, ":") && strings.ToUpper(word) != word {
+		// Only "error:", "[error]" or "ERROR", not a sentence starting
+		// with "Error".
+		return def, msg
+	}
+	if name == "warning" {
+		name = "warn"
+	}
+	lv, err := ParseLevel(name)
+	if err != nil {
+		return def, msg
+	}
+	return lv, strings.TrimLeft(msg[len(word):], " \t")
+}
+
+// stdlibCaller returns the first caller outside the log package.
+func stdlibCaller() *callerRef {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "log.") {
+			return &callerRef{c: Caller{Function: f.Function, File: f.File, Line: f.Line}}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
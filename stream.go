@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+type DecodeOptions struct {
+	// BackfillTime takes the time of entries serialized without one from a
+	// timestamp at the start of their message. Otherwise, and when no
+	// timestamp is found, the time the entry was read is used.
+	BackfillTime bool
+}
+
+// DecodeStream reads NDJSON entries as written with FormatJSON, resolving
+// the messages of files written with a message dictionary. Entries are
+// sent on the first channel; decoding stops at the first malformed line or
+// read error, which is sent on the second channel. Both channels are closed
+// when the stream ends.
+func DecodeStream(r io.Reader) (<-chan Entry, <-chan error) {
+	return DecodeStreamOptions(r, DecodeOptions{})
+}
+
+func DecodeStreamOptions(r io.Reader, opts DecodeOptions) (<-chan Entry, <-chan error) {
+	entries := make(chan Entry)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(entries)
+		defer close(errc)
+
+		br := bufio.NewReader(r)
+		var dict dictDecoder
+		for n := 1; ; n++ {
+			line, err := br.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				e, ok, err := dict.decode(line)
+				if err != nil {
+					errc <- fmt.Errorf("line %d: %w", n, err)
+					return
+				}
+				if ok {
+					backfillTime(&e, time.Now(), opts.BackfillTime)
+					entries <- e
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+	return entries, errc
+}
@@ -0,0 +1,22 @@
+package logger
+
+// Tag returns a logger attaching the given tags to every entry, in addition
+// to the tags of l. Tags are short labels such as "db" or "retry", shown as
+// chips on the console and matched by filters with `tags contains "db"`.
+func (l *Logger) Tag(tags ...string) *Logger {
+	if len(tags) == 0 {
+		return l
+	}
+	child := *l
+	child.tags = append(l.tags[:len(l.tags):len(l.tags)], tags...)
+	return &child
+}
+
+func (e Entry) hasTag(tag string) bool {
+	for _, t := range e.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
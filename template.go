@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Formatter renders console entries in a custom layout. Format returns the
+// text of e without the trailing newline, which the logger adds.
+type Formatter interface {
+	Format(e Entry) []byte
+}
+
+// FormatterFunc adapts a function to Formatter.
+type FormatterFunc func(e Entry) []byte
+
+func (f FormatterFunc) Format(e Entry) []byte {
+	return f(e)
+}
+
+// TemplateData is what a Config.Template is executed with.
+type TemplateData struct {
+	// Level is the level name, e.g. "info"; Badge is the styled console
+	// badge, e.g. "[ INFO ]".
+	Level Level
+	Badge string
+	// Time prints as "2006/01/02 15:04:05"; use {{.Time.Format "15:04"}}
+	// for another layout.
+	Time TemplateTime
+	// Caller is "function:line", empty for entries without a caller.
+	Caller string
+	Msg    string
+	Name   string
+	Tags   []string
+	// Fields are the rendered key=value pairs, empty without fields.
+	Fields string
+	// Entry gives access to everything else.
+	Entry Entry
+}
+
+// TemplateTime is the entry time in a template.
+type TemplateTime struct {
+	time.Time
+}
+
+func (t TemplateTime) String() string {
+	return formatDate(t.Time) + " " + formatTime(t.Time)
+}
+
+// ParseTemplate returns a Formatter executing the text/template text with
+// TemplateData, e.g. "{{.Badge}} {{.Time}} {{.Msg}} {{.Fields}}". Blocks
+// such as stack traces and dumps follow on their own lines.
+func ParseTemplate(text string) (Formatter, error) {
+	t, err := template.New("entry").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &templateFormatter{t: t, f: plainFormatter}, nil
+}
+
+type templateFormatter struct {
+	t *template.Template
+	f *formatter // styles the badge and field keys
+}
+
+func (tf *templateFormatter) Format(e Entry) []byte {
+	th := tf.f.theme
+	data := TemplateData{
+		Level: e.level,
+		Badge: "[" + (th.Badge + th.Levels[e.level]).render(tf.f.badge(e.level)) + "]",
+		Time:  TemplateTime{e.time},
+		Msg:   e.message,
+		Name:  e.name,
+		Tags:  e.tags,
+		Entry: e,
+	}
+	if caller := e.Caller(); !caller.IsZero() {
+		data.Caller = caller.Function + ":" + strconv.Itoa(caller.Line)
+	}
+	var fields strings.Builder
+	appendFields(&fields, "", e.fields, th.FieldKey.render)
+	data.Fields = fields.String()
+
+	var b bytes.Buffer
+	if err := tf.t.Execute(&b, data); err != nil {
+		b.WriteString("template error: " + err.Error())
+	}
+	b.WriteString(blockLines(e.fields, th))
+	return b.Bytes()
+}
+
+// withFormatter returns a copy of tf styled by f.
+func (tf *templateFormatter) withFormatter(f *formatter) *templateFormatter {
+	return &templateFormatter{t: tf.t, f: f}
+}
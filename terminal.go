@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+func isUTF8Locale() bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+func unicodeSupported() bool {
+	return isUTF8Locale() || os.Getenv("WT_SESSION") != ""
+}
+
+// ColorDepth is the number of colors a terminal can display.
+type ColorDepth int
+
+const (
+	ColorNone ColorDepth = iota
+	Color16
+	Color256
+	ColorTrueColor
+)
+
+func (d ColorDepth) String() string {
+	switch d {
+	case ColorNone:
+		return "none"
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	case ColorTrueColor:
+		return "truecolor"
+	}
+	return fmt.Sprintf("colordepth(%d)", int(d))
+}
+
+// Terminal describes the capabilities of the terminal an output is
+// attached to.
+type Terminal struct {
+	// IsTerminal is false when the output is redirected to a file or pipe.
+	IsTerminal bool
+	// Colors is ColorNone when the output is not a terminal, NO_COLOR is
+	// set or the terminal is dumb.
+	Colors ColorDepth
+	// Unicode reports whether icons and box drawing characters can be
+	// printed, based on the locale.
+	Unicode bool
+	// Light reports a light background, as announced by COLORFGBG.
+	Light bool
+}
+
+// TerminalInfo probes the terminal of os.Stdout, which is the one the
+// console output is adapted to by default.
+func TerminalInfo() Terminal {
+	return ProbeTerminal(os.Stdout)
+}
+
+// ProbeTerminal detects the capabilities of the terminal f is attached to
+// from its file mode and the environment (TERM, COLORTERM, TERM_PROGRAM,
+// NO_COLOR), without cgo.
+func ProbeTerminal(f *os.File) Terminal {
+	t := Terminal{
+		IsTerminal: f != nil && isTerminal(f),
+		Unicode:    unicodeSupported(),
+		Light:      lightBackground(),
+	}
+	if t.IsTerminal && ansiSupported && os.Getenv("NO_COLOR") == "" {
+		t.Colors = colorDepth()
+	}
+	return t
+}
+
+// lightBackground reads the background color from COLORFGBG ("15;0" or
+// "0;default;15"): white, light gray and the bright colors but dark gray
+// are light.
+func lightBackground() bool {
+	v := os.Getenv("COLORFGBG")
+	bg := v[strings.LastIndexByte(v, ';')+1:]
+	switch bg {
+	case "7", "9", "10", "11", "12", "13", "14", "15":
+		return v != bg
+	}
+	return false
+}
+
+func colorDepth() ColorDepth {
+	switch ct := strings.ToLower(os.Getenv("COLORTERM")); ct {
+	case "truecolor", "24bit":
+		return ColorTrueColor
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return ColorTrueColor
+	case "Apple_Terminal":
+		return Color256
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return ColorTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return ColorNone
+	case term == "" && runtime.GOOS != "windows":
+		return ColorNone
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"),
+		strings.Contains(term, "direct"), strings.Contains(term, "kitty"),
+		strings.Contains(term, "alacritty"):
+		return ColorTrueColor
+	case strings.Contains(term, "256color"):
+		return Color256
+	}
+	return Color16
+}
+
+// outputTerminal probes w when it is a file. Other writers are never
+// terminals.
+func outputTerminal(w io.Writer) Terminal {
+	if f, ok := w.(*os.File); ok {
+		return ProbeTerminal(f)
+	}
+	return Terminal{}
+}
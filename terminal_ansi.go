@@ -0,0 +1,5 @@
+//go:build !js && !wasip1 && !tinygo
+
+package logger
+
+const ansiSupported = true
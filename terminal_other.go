@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+
+package logger
+
+import "os"
+
+// isTerminal falls back to the file mode, which can't tell a tty from other
+// character devices.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,7 @@
+//go:build js || wasip1 || tinygo
+
+package logger
+
+// ansiSupported is false where the output usually isn't a terminal (browser
+// consoles, wasm hosts, microcontrollers), so styles are never rendered.
+const ansiSupported = false
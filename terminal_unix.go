@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal asks for the terminal attributes of f, which only a tty has;
+// a character device such as /dev/null fails.
+func isTerminal(f *os.File) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlGetTermios, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// TestingT is the subset of testing.TB used by ForTest.
+type TestingT interface {
+	Name() string
+	Helper()
+	Cleanup(func())
+	Log(args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type TestOption func(*testOptions)
+
+type testOptions struct {
+	allowErrors bool
+}
+
+// AllowErrors keeps ForTest from failing the test when errors are logged.
+func AllowErrors() TestOption {
+	return func(o *testOptions) {
+		o.allowErrors = true
+	}
+}
+
+// ForTest returns a logger named after the test which writes through t.Log.
+// When the test ends, sinks are flushed and the test fails if any Error or
+// Alert entries were logged, unless AllowErrors is given.
+func ForTest(t TestingT, opts ...TestOption) *Logger {
+	t.Helper()
+	var o testOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	errs := &errorCounter{}
+	l := New(&Config{
+		Name:   t.Name(),
+		Output: testWriter{t},
+		Theme:  &Theme{},
+		Sinks:  []Sink{errs},
+	})
+	t.Cleanup(func() {
+		t.Helper()
+		l.flushSinks()
+		if o.allowErrors {
+			return
+		}
+		if n, first := errs.result(); n > 0 {
+			t.Errorf("logger: %d error entries were logged, first: %s", n, first)
+		}
+	})
+	return l
+}
+
+type testWriter struct {
+	t TestingT
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+type errorCounter struct {
+	mu    sync.Mutex
+	n     int
+	first string
+}
+
+func (c *errorCounter) WriteEntry(e Entry) error {
+	if e.level < LevelError {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n == 0 {
+		c.first = e.message
+	}
+	c.n++
+	return nil
+}
+
+func (c *errorCounter) result() (int, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n, c.first
+}
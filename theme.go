@@ -0,0 +1,153 @@
+package logger
+
+import "path"
+
+// Style is a sequence of ANSI escape codes applied to an element of the
+// console output. An empty Style leaves the element unstyled, as does every
+// Style under js/wasm, wasip1 and tinygo.
+type Style string
+
+func (s Style) render(text string) string {
+	if s == "" || !ansiSupported {
+		return text
+	}
+	return string(s) + text + reset
+}
+
+// Theme controls the styling of each element of the console output. Use
+// DefaultTheme as a starting point; a zero Theme prints without any styling.
+type Theme struct {
+	// Levels holds the color of each level, used for badges, icons and
+	// tinted messages.
+	Levels map[Level]Style
+	// Highlights is drawn behind the message of caller-aware entries.
+	Highlights map[Level]Style
+	// Markers colors the "↳" marker of caller-aware entries per level.
+	Markers map[Level]Style
+
+	Badge    Style
+	Date     Style
+	Time     Style
+	Uptime   Style
+	Name     Style
+	Caller   Style
+	Line     Style
+	Message  Style // message printed on the header line (Info, Warn)
+	Detail   Style // message printed on the "↳" line
+	FieldKey Style
+	Tag      Style
+
+	// DumpType, DumpString and DumpLiteral style the type names, strings
+	// and other scalars of Dump and Pretty trees; keys use FieldKey.
+	DumpType    Style
+	DumpString  Style
+	DumpLiteral Style
+
+	// Names styles the name column of matching loggers instead of Name.
+	// Keys are names or path.Match patterns such as "api.*"; the longest
+	// matching pattern wins.
+	Names map[string]Style
+	// Tags styles the chips of the given tags instead of Tag.
+	Tags map[string]Style
+
+	// TintMessage colors messages with the level color instead of Message
+	// and Detail, in bold for errors and alerts.
+	TintMessage bool
+}
+
+func DefaultTheme() *Theme {
+	return &Theme{
+		Levels: map[Level]Style{
+			LevelDebug: magenta,
+			LevelInfo:  brightGreen,
+			LevelWarn:  orange,
+			LevelError: red,
+			LevelAlert: blue,
+			LevelPanic: brightMagenta,
+			LevelFatal: brightRed,
+		},
+		Highlights: map[Level]Style{
+			LevelError: bgRed,
+			LevelAlert: bgBlue,
+			LevelPanic: bgMagenta,
+			LevelFatal: bgRed,
+		},
+		Badge:    bold,
+		Date:     dim + italic,
+		Time:     underline,
+		Uptime:   dim,
+		Name:     brightCyan,
+		Caller:   brightBlue,
+		Line:     bold,
+		Message:  bold,
+		Detail:   bold + brightYellow,
+		FieldKey: cyan,
+		Tag:      bgBrightBlack + white,
+
+		DumpType:    brightBlue,
+		DumpString:  green,
+		DumpLiteral: yellow,
+	}
+}
+
+// LightTheme is DefaultTheme with colors readable on light backgrounds,
+// chosen by default when COLORFGBG announces one.
+func LightTheme() *Theme {
+	t := DefaultTheme()
+	t.Levels[LevelInfo] = green
+	t.Levels[LevelWarn] = Fg256(130) // dark orange
+	t.Levels[LevelPanic] = magenta
+	t.Levels[LevelFatal] = bold + red
+	t.Name = cyan
+	t.Caller = blue
+	t.Detail = bold + blue
+	t.DumpType = blue
+	t.DumpLiteral = Fg256(130)
+	return t
+}
+
+// iconTheme is the softer variant of base used in icon mode.
+func iconTheme(base *Theme) *Theme {
+	t := *base
+	t.Highlights = nil
+	t.Badge = ""
+	t.Message = ""
+	t.Detail = ""
+	return &t
+}
+
+func (t *Theme) name(name string) Style {
+	if s, ok := t.Names[name]; ok {
+		return s
+	}
+	style, best := t.Name, ""
+	for pattern, s := range t.Names {
+		if len(pattern) < len(best) || len(pattern) == len(best) && pattern > best {
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			style, best = s, pattern
+		}
+	}
+	return style
+}
+
+func (t *Theme) tag(tag string) Style {
+	if s, ok := t.Tags[tag]; ok {
+		return s
+	}
+	return t.Tag
+}
+
+func (t *Theme) message(lv Level, detail bool) Style {
+	if t.TintMessage {
+		if lv >= LevelError {
+			return bold + t.Levels[lv]
+		}
+		return t.Levels[lv]
+	}
+	if detail {
+		return t.Highlights[lv] + t.Detail
+	}
+	return t.Message
+}
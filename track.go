@@ -0,0 +1,28 @@
+package logger
+
+import "time"
+
+// Track starts timing name and returns a function logging the time elapsed
+// since, meant to be deferred at the top of the function being profiled:
+//
+//	defer log.Track("checkout")()
+//
+// The entry is an Info entry with the caller of Track and a duration
+// field, or a Warn entry marked slow=true once Config.SlowThreshold is
+// exceeded.
+func (l *Logger) Track(name string) func() {
+	start := time.Now()
+	caller := l.caller(LevelInfo)
+	return func() {
+		elapsed := time.Since(start)
+		fields := []Field{{Key: "duration", Value: elapsed}}
+		level := LevelInfo
+		if t := l.c.SlowThreshold; t > 0 && elapsed >= t {
+			level = LevelWarn
+			fields = append(fields, Field{Key: "slow", Value: true})
+		}
+		if l.enabled(level) {
+			l.log(makeEntry(level, name, fields, caller))
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package tui is a bubbletea component showing a live, scrollable and
+// filterable view of a logger.Ring.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pecet3/logger"
+)
+
+const refreshInterval = 250 * time.Millisecond
+
+var levelKeys = map[string]logger.Level{
+	"1": logger.LevelDebug,
+	"2": logger.LevelInfo,
+	"3": logger.LevelWarn,
+	"4": logger.LevelError,
+	"5": logger.LevelAlert,
+	"6": logger.LevelPanic,
+	"7": logger.LevelFatal,
+}
+
+var levelStyles = map[logger.Level]lipgloss.Style{
+	logger.LevelDebug: lipgloss.NewStyle().Foreground(lipgloss.Color("5")).Bold(true),
+	logger.LevelInfo:  lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true),
+	logger.LevelWarn:  lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true),
+	logger.LevelError: lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true),
+	logger.LevelAlert: lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Bold(true),
+	logger.LevelPanic: lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Bold(true),
+	logger.LevelFatal: lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true),
+}
+
+var (
+	dimStyle    = lipgloss.NewStyle().Faint(true)
+	callerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	barStyle    = lipgloss.NewStyle().Reverse(true)
+)
+
+type tickMsg time.Time
+
+// Model is the viewer component. Embed it in another model and forward
+// messages to Update, or use Run for a standalone viewer.
+type Model struct {
+	ring *logger.Ring
+
+	entries []logger.Entry
+	hidden  map[logger.Level]bool
+	query   string
+	typing  bool
+
+	// offset is the number of lines scrolled up from the newest entry.
+	offset int
+	width  int
+	height int
+}
+
+func New(ring *logger.Ring) Model {
+	m := Model{
+		ring:   ring,
+		hidden: make(map[logger.Level]bool),
+		height: 20,
+	}
+	m.refresh()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return tick()
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// SetSize sets the area available to the component.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.refresh()
+		return m, tick()
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		if m.typing {
+			m.updateQuery(msg)
+			return m, nil
+		}
+		switch key := msg.String(); key {
+		case "up", "k":
+			m.scroll(1)
+		case "down", "j":
+			m.scroll(-1)
+		case "pgup":
+			m.scroll(m.pageSize())
+		case "pgdown":
+			m.scroll(-m.pageSize())
+		case "g", "home":
+			m.scroll(len(m.entries))
+		case "G", "end":
+			m.offset = 0
+		case "/":
+			m.typing = true
+		case "esc":
+			m.query = ""
+			m.refresh()
+		default:
+			if lv, ok := levelKeys[key]; ok {
+				m.hidden[lv] = !m.hidden[lv]
+				m.refresh()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) updateQuery(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.typing = false
+	case tea.KeyEsc:
+		m.typing = false
+		m.query = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	}
+	m.offset = 0
+	m.refresh()
+}
+
+func (m *Model) refresh() {
+	all := m.ring.Entries()
+	m.entries = make([]logger.Entry, 0, len(all))
+	query := strings.ToLower(m.query)
+	for _, e := range all {
+		if m.hidden[e.Level()] {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(e.Message()), query) {
+			continue
+		}
+		m.entries = append(m.entries, e)
+	}
+	m.scroll(0)
+}
+
+func (m *Model) scroll(n int) {
+	m.offset += n
+	if limit := len(m.entries) - m.pageSize(); m.offset > limit {
+		m.offset = limit
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+func (m Model) pageSize() int {
+	if m.height <= 1 {
+		return 1
+	}
+	return m.height - 1
+}
+
+func (m Model) View() string {
+	end := len(m.entries) - m.offset
+	start := max(end-m.pageSize(), 0)
+
+	var b strings.Builder
+	for _, e := range m.entries[start:end] {
+		b.WriteString(m.line(e))
+		b.WriteByte('\n')
+	}
+	for i := end - start; i < m.pageSize(); i++ {
+		b.WriteByte('\n')
+	}
+	b.WriteString(m.statusBar())
+	return b.String()
+}
+
+func (m Model) line(e logger.Entry) string {
+	parts := []string{
+		dimStyle.Render(e.Time().Format("15:04:05")),
+		levelStyles[e.Level()].Render(fmt.Sprintf("%-5s", strings.ToUpper(e.Level().String()))),
+	}
+	if c := e.Caller(); !c.IsZero() {
+		parts = append(parts, callerStyle.Render(fmt.Sprintf("%s:%d", c.Function, c.Line)))
+	}
+	parts = append(parts, e.Message())
+	line := strings.Join(parts, " ")
+	if m.width > 0 {
+		line = lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+	}
+	return line
+}
+
+func (m Model) statusBar() string {
+	var levels []string
+	for _, key := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		lv := levelKeys[key]
+		name := key + ":" + lv.String()
+		if m.hidden[lv] {
+			name = dimStyle.Render(name)
+		} else {
+			name = levelStyles[lv].Render(name)
+		}
+		levels = append(levels, name)
+	}
+	filter := "/ filter"
+	if m.typing || m.query != "" {
+		filter = "/" + m.query
+		if m.typing {
+			filter += "█"
+		}
+	}
+	status := fmt.Sprintf(" %s  %s  %d entries", strings.Join(levels, " "), filter, len(m.entries))
+	if m.offset > 0 {
+		status += fmt.Sprintf("  (+%d below)", m.offset)
+	}
+	if m.width > 0 {
+		return barStyle.Width(m.width).MaxWidth(m.width).Render(status)
+	}
+	return barStyle.Render(status)
+}
+
+type standalone struct {
+	Model
+}
+
+func (s standalone) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && !s.typing {
+		switch key.String() {
+		case "q", "ctrl+c":
+			return s, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	s.Model, cmd = s.Model.Update(msg)
+	return s, cmd
+}
+
+// Run shows the viewer full screen until q or ctrl+c is pressed.
+func Run(ring *logger.Ring) error {
+	_, err := tea.NewProgram(standalone{New(ring)}, tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// numLevels is the number of levels counted by volume; higher levels are
+// counted as LevelFatal.
+const numLevels = int(LevelFatal) + 1
+
+// volumeTier counts entries per level in fixed-size time slots, keeping the
+// last len(slots) of them.
+type volumeTier struct {
+	res   time.Duration
+	slots [][numLevels]uint32
+	ids   []int64 // time slot each position holds
+}
+
+func newVolumeTier(res time.Duration, n int) volumeTier {
+	return volumeTier{res: res, slots: make([][numLevels]uint32, n), ids: make([]int64, n)}
+}
+
+func (t *volumeTier) add(at time.Time, lv Level) {
+	id := at.UnixNano() / int64(t.res)
+	pos := t.pos(id)
+	if t.ids[pos] != id {
+		t.ids[pos] = id
+		t.slots[pos] = [numLevels]uint32{}
+	}
+	t.slots[pos][lv]++
+}
+
+// slot returns the counts of the slot starting at id*res, if still kept.
+func (t *volumeTier) slot(id int64) ([numLevels]uint32, bool) {
+	pos := t.pos(id)
+	if t.ids[pos] != id {
+		return [numLevels]uint32{}, false
+	}
+	return t.slots[pos], true
+}
+
+// pos returns the position of slot id, which is negative before 1970.
+func (t *volumeTier) pos(id int64) int64 {
+	n := int64(len(t.slots))
+	return (id%n + n) % n
+}
+
+func (t *volumeTier) span() time.Duration {
+	return t.res * time.Duration(len(t.slots))
+}
+
+// volume counts the logged entries per level, per second over the last ten
+// minutes and per minute over the last day. It is shared by the loggers
+// derived from a logger.
+type volume struct {
+	mu      sync.Mutex
+	started bool
+	seconds volumeTier
+	minutes volumeTier
+}
+
+// init allocates the tiers on first use, so loggers that never log do not
+// pay for them.
+func (v *volume) init() {
+	if v.started {
+		return
+	}
+	v.started = true
+	v.seconds = newVolumeTier(time.Second, 600)
+	v.minutes = newVolumeTier(time.Minute, 24*60)
+}
+
+func (v *volume) add(e Entry) {
+	if v == nil {
+		return
+	}
+	lv := min(max(e.level, LevelDebug), LevelFatal)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.init()
+	v.seconds.add(e.time, lv)
+	v.minutes.add(e.time, lv)
+}
+
+// VolumeBucket is the number of entries per level logged in a period.
+type VolumeBucket struct {
+	Start  time.Time
+	End    time.Time
+	Counts map[Level]int
+}
+
+func (b VolumeBucket) Total() int {
+	n := 0
+	for _, c := range b.Counts {
+		n += c
+	}
+	return n
+}
+
+// VolumeHistogram splits the last window into the given number of buckets
+// and returns how many entries of every level were logged in each, oldest
+// first, e.g. to render activity sparklines. Counts are kept per second for
+// the last ten minutes and per minute for the last day, so longer windows
+// are cut to a day and buckets shorter than the resolution share slots with
+// their neighbors.
+func (l *Logger) VolumeHistogram(window time.Duration, buckets int) []VolumeBucket {
+	if buckets <= 0 || window <= 0 || l.vol == nil {
+		return nil
+	}
+	return l.vol.histogram(time.Now(), window, buckets)
+}
+
+func (v *volume) histogram(now time.Time, window time.Duration, buckets int) []VolumeBucket {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.init()
+	tier := &v.seconds
+	if window > v.seconds.span() || window/time.Duration(buckets) >= time.Minute {
+		tier = &v.minutes
+	}
+	window = min(window, v.minutes.span())
+
+	end := now.Truncate(tier.res).Add(tier.res)
+	start := end.Add(-window)
+	width := max(window/time.Duration(buckets), 1)
+	out := make([]VolumeBucket, buckets)
+	for i := range out {
+		out[i] = VolumeBucket{
+			Start:  start.Add(time.Duration(i) * width),
+			End:    start.Add(time.Duration(i+1) * width),
+			Counts: make(map[Level]int),
+		}
+	}
+	res := int64(tier.res)
+	for id := start.UnixNano() / res; id < end.UnixNano()/res; id++ {
+		counts, ok := tier.slot(id)
+		if !ok {
+			continue
+		}
+		i := int((id*res - start.UnixNano()) / int64(width))
+		i = min(max(i, 0), buckets-1)
+		for lv, n := range counts {
+			if n > 0 {
+				out[i].Counts[Level(lv)] += int(n)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges are the East Asian Wide and Fullwidth characters and the
+// emoji presented as such, which take two terminal cells.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+	{0x17000, 0x18CFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251}, {0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F9FF}, {0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+func isWide(r rune) bool {
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	return i < len(wideRanges) && wideRanges[i][0] <= r
+}
+
+// isZeroWidth reports whether r extends the previous character rather than
+// taking cells of its own: combining marks, variation selectors, the zero
+// width joiner and emoji skin tone modifiers.
+func isZeroWidth(r rune) bool {
+	return unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) ||
+		r == 0x200D || r >= 0xFE00 && r <= 0xFE0F || r >= 0xE0100 && r <= 0xE01EF ||
+		r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// nextCluster returns the length in bytes and the width in terminal cells of
+// the grapheme cluster at the start of s. Clusters are approximated as a
+// character with its zero width extenders, ZWJ emoji sequences and regional
+// indicator pairs, which covers what badges and icons use.
+func nextCluster(s string) (size, width int) {
+	r, n := utf8.DecodeRuneInString(s)
+	size = n
+	switch {
+	case isWide(r):
+		width = 2
+	case isZeroWidth(r) || unicode.IsControl(r):
+		width = 0
+	default:
+		width = 1
+	}
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[size:]); isRegionalIndicator(r2) {
+			return size + n2, 2
+		}
+		return size, 1
+	}
+	for size < len(s) {
+		r, n := utf8.DecodeRuneInString(s[size:])
+		if !isZeroWidth(r) {
+			break
+		}
+		size += n
+		switch r {
+		case 0xFE0F:
+			// VS16 selects the two-cell emoji presentation.
+			width = 2
+		case 0x200D:
+			if size < len(s) {
+				_, n := utf8.DecodeRuneInString(s[size:])
+				size += n
+			}
+		}
+	}
+	return size, width
+}
+
+// displayWidth is the number of terminal cells s takes.
+func displayWidth(s string) int {
+	w := 0
+	for len(s) > 0 {
+		n, cw := nextCluster(s)
+		s, w = s[n:], w+cw
+	}
+	return w
+}
+
+// truncateWidth cuts s to at most width cells without splitting a grapheme
+// cluster, and returns the result with its width.
+func truncateWidth(s string, width int) (string, int) {
+	i, w := 0, 0
+	for i < len(s) {
+		n, cw := nextCluster(s[i:])
+		if w+cw > width {
+			break
+		}
+		i, w = i+n, w+cw
+	}
+	return s[:i], w
+}
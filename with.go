@@ -0,0 +1,55 @@
+package logger
+
+// WithFields returns a logger attaching the given fields to every entry,
+// before the fields of the log call and after those of l:
+//
+//	reqLog := l.WithFields(logger.Fields{"request_id": id})
+//	reqLog.Info("handled") // ... handled request_id=7f3a
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := *l
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], marshalFields(Fields(fields))...)
+	return &child
+}
+
+// With returns a logger attaching the given fields to every entry, like
+// WithFields but keeping their order:
+//
+//	dbLog := l.With(logger.Field{Key: "db", Value: "orders"})
+func (l *Logger) With(fields ...LogMarshaler) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	child := *l
+	child.fields = l.fields[:len(l.fields):len(l.fields)]
+	for _, m := range fields {
+		child.fields = append(child.fields, marshalFields(m)...)
+	}
+	return &child
+}
+
+// Named returns a logger for a component of l, whose entries carry the
+// name of l and the given one joined with a dot, e.g. "api.http", so
+// Theme.Names patterns such as "api.*" style a whole subsystem.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	child := *l
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	child.name = name
+	return &child
+}
+
+// CallerSkip returns a logger taking the caller of its entries n more
+// frames up the stack, for wrappers around l that would otherwise be
+// reported as the caller.
+func (l *Logger) CallerSkip(n int) *Logger {
+	child := *l
+	child.skip += n
+	return &child
+}
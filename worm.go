@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"os"
+	"time"
+)
+
+// WORM makes log files write-once, read-many for regulated environments.
+// Set it in FileOptions: the files are only ever opened for appending,
+// made read-only once the sink rolls over to the next day, and neither the
+// Retention nor the Archiver of the sink deletes them before Period has
+// passed since their last write. Every deletion attempt is logged.
+type WORM struct {
+	// Period is how long a file is kept after its last write.
+	Period time.Duration
+	// Logger, when set, receives a Warn entry for every refused deletion and
+	// an Info entry for every allowed one.
+	Logger *Logger
+}
+
+type wormEvent struct {
+	path     string
+	modified time.Time
+	until    time.Time
+	reason   string
+}
+
+func (ev wormEvent) MarshalLog(enc FieldEncoder) {
+	enc.AddString("path", ev.path)
+	enc.AddTime("modified", ev.modified)
+	enc.AddTime("locked_until", ev.until)
+	enc.AddString("reason", ev.reason)
+}
+
+// allowDelete reports whether the file at path is out of its retention
+// period, logging the attempt to delete it for reason.
+func (w *WORM) allowDelete(path string, reason string) bool {
+	if w == nil {
+		return true
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if w.Logger != nil {
+			w.Logger.Warn("refused to delete locked log file, stat err: ", err)
+		}
+		return false
+	}
+	ev := wormEvent{path: path, modified: info.ModTime(), until: info.ModTime().Add(w.Period), reason: reason}
+	if time.Now().Before(ev.until) {
+		if w.Logger != nil {
+			w.Logger.Warn("refused to delete locked log file", ev)
+		}
+		return false
+	}
+	if w.Logger != nil {
+		w.Logger.Info("deleting log file past its lock", ev)
+	}
+	return true
+}
+
+// override logs the rewrite of the file at path, last modified at
+// modified, for reason if it was still locked, e.g. for a right-to-erasure
+// request.
+func (w *WORM) override(path string, modified time.Time, reason string) {
+	if w == nil || w.Logger == nil {
+		return
+	}
+	ev := wormEvent{path: path, modified: modified, until: modified.Add(w.Period), reason: reason}
+	if time.Now().Before(ev.until) {
+		w.Logger.Warn("overrode the lock of a log file", ev)
+	}
+}
+
+// seal makes a closed file read-only so it cannot be truncated or
+// rewritten in place.
+func (w *WORM) seal(path string) error {
+	if w == nil {
+		return nil
+	}
+	return os.Chmod(path, 0444)
+}